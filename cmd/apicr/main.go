@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/apicurio/apicurio-cli/internal/build"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
 	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	coreErrors "github.com/apicurio/apicurio-cli/pkg/core/errors"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/tracing"
 
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory/defaultfactory"
@@ -40,11 +47,18 @@ func main() {
 
 	err = rootCmd.Execute()
 
+	finishTrace(cmdFactory, err)
+
 	if err == nil {
 		return
 	}
 
 	cmdFactory.Logger.Errorf("%v\n", rootError(err, localizer))
+
+	var exitErr *coreErrors.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	}
 	os.Exit(1)
 }
 
@@ -71,7 +85,8 @@ func firstCharToUpper(message string) string {
 func initConfig(f *factory.Factory) {
 	cfgFile, err := f.Config.Load()
 
-	if cfgFile != nil {
+	// the commands which need the credentials report why they cannot be loaded
+	if cfgFile != nil || errors.Is(err, config.ErrCredentials) {
 		return
 	}
 	if !os.IsNotExist(err) {
@@ -79,9 +94,38 @@ func initConfig(f *factory.Factory) {
 		os.Exit(1)
 	}
 
-	cfgFile = &config.Config{}
-	if err := f.Config.Save(cfgFile); err != nil {
+	// the file is created under the lock, so that the config written by a concurrent process is kept
+	if err := f.Config.Update(func(*config.Config) error { return nil }); err != nil {
 		fmt.Fprintln(f.IOStreams.ErrOut, err)
 		os.Exit(1)
 	}
 }
+
+// traceExportTimeout is the time the trace can take to be sent to the collector
+const traceExportTimeout = 10 * time.Second
+
+// finishTrace prints the time taken by the traced command and its requests, and exports the trace
+func finishTrace(f *factory.Factory, err error) {
+	trace := tracing.Active()
+	if trace == nil {
+		return
+	}
+	trace.Finish(err)
+
+	fmt.Fprintln(f.IOStreams.ErrOut)
+	_ = trace.WriteSummary(f.IOStreams.ErrOut)
+
+	resource := tracing.Resource{ServiceName: "apicr", ServiceVersion: build.Version}
+	if path := flagutil.TraceFile(); path != "" {
+		if err := trace.ExportFile(path, resource); err != nil {
+			fmt.Fprintln(f.IOStreams.ErrOut, icon.ErrorPrefix(), err)
+		}
+	}
+	if endpoint := flagutil.TraceEndpoint(); endpoint != "" {
+		ctx, cancel := context.WithTimeout(f.Context, traceExportTimeout)
+		defer cancel()
+		if err := trace.ExportOTLP(ctx, http.DefaultClient, endpoint, resource); err != nil {
+			fmt.Fprintln(f.IOStreams.ErrOut, icon.ErrorPrefix(), err)
+		}
+	}
+}
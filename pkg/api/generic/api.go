@@ -3,14 +3,46 @@ package generic
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
 type GenericAPI interface {
 	GET(ctx context.Context, path string) (interface{}, *http.Response, error)
 	POST(ctx context.Context, path string, body io.Reader) (interface{}, *http.Response, error)
+	// Do performs a request with any HTTP method, returning the response even when its status is an error status
+	Do(ctx context.Context, request *Request) (*Response, error)
+}
+
+// Request describes an HTTP request to perform against the API server
+type Request struct {
+	// Method is the HTTP method, for example GET or PATCH
+	Method string
+	// Path is resolved against the base URL and can contain a query string
+	Path string
+	// Query parameters to add to the query string of the path
+	Query url.Values
+	// Header contains the request headers
+	Header http.Header
+	// Body is the optional request body
+	Body io.Reader
+}
+
+// Response is the response returned by the API server
+type Response struct {
+	Proto      string
+	Status     string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// IsError reports whether the response status code is a client or server error
+func (r *Response) IsError() bool {
+	return r.StatusCode >= http.StatusBadRequest
 }
 
 // APIConfig defines the available configuration options
@@ -43,24 +75,37 @@ type APIClient struct {
 }
 
 func (c *APIClient) GET(ctx context.Context, path string) (interface{}, *http.Response, error) {
-	url := c.baseURL + path
+	return c.doString(ctx, &Request{
+		Method: http.MethodGet,
+		Path:   path,
+		Header: http.Header{"Accept": []string{"application/json"}},
+	})
+}
+
+func (c *APIClient) POST(ctx context.Context, path string, body io.Reader) (interface{}, *http.Response, error) {
+	return c.doString(ctx, &Request{
+		Method: http.MethodPost,
+		Path:   path,
+		Header: http.Header{
+			"Accept":       []string{"application/json"},
+			"Content-Type": []string{"application/json"},
+		},
+		Body: body,
+	})
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// doString performs the request and returns the response body as a string,
+// returning an error for responses with an error status
+func (c *APIClient) doString(ctx context.Context, request *Request) (interface{}, *http.Response, error) {
+	req, err := c.newRequest(ctx, request)
 	if err != nil {
-		return err, nil, err
+		return nil, nil, err
 	}
 
-	req = req.WithContext(ctx)
-
-	req.Header.Set("Accept", "application/json")
-
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, resp, err
 	}
-	if resp.StatusCode > http.StatusBadRequest {
-		return nil, resp, errors.New(resp.Status)
-	}
 	defer resp.Body.Close()
 
 	b, err := io.ReadAll(resp.Body)
@@ -68,39 +113,72 @@ func (c *APIClient) GET(ctx context.Context, path string) (interface{}, *http.Re
 		return nil, resp, err
 	}
 
-	return string(b), resp, err
+	if resp.StatusCode >= http.StatusBadRequest {
+		return string(b), resp, errors.New(resp.Status)
+	}
+
+	return string(b), resp, nil
 }
 
-func (c *APIClient) POST(ctx context.Context, path string, body io.Reader) (interface{}, *http.Response, error) {
-	url := c.baseURL + path
-	bodyBinary, err := io.ReadAll(body)
+// Do performs the request and returns the full response.
+// Error statuses do not result in an error, so that their body can be inspected.
+func (c *APIClient) Do(ctx context.Context, request *Request) (*Response, error) {
+	req, err := c.newRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err, nil, err
+		return nil, err
 	}
-	requestBody := strings.NewReader(string(bodyBinary))
-	req, err := http.NewRequest("POST", url, requestBody)
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	return &Response{
+		Proto:      resp.Proto,
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       b,
+	}, nil
+}
 
-	resp, err := c.httpClient.Do(req)
+func (c *APIClient) newRequest(ctx context.Context, request *Request) (*http.Request, error) {
+	u, err := url.Parse(c.baseURL + request.Path)
 	if err != nil {
-		return nil, resp, err
+		return nil, fmt.Errorf("invalid request path %q: %w", request.Path, err)
 	}
-	if resp.StatusCode > http.StatusBadRequest {
-		return nil, resp, errors.New(resp.Status)
+
+	if len(request.Query) > 0 {
+		q := u.Query()
+		for key, values := range request.Query {
+			for _, v := range values {
+				q.Add(key, v)
+			}
+		}
+		u.RawQuery = q.Encode()
 	}
-	defer resp.Body.Close()
 
-	b, err := io.ReadAll(resp.Body)
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), request.Body)
 	if err != nil {
-		return nil, resp, err
+		return nil, err
+	}
+
+	for key, values := range request.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
 	}
 
-	return string(b), resp, err
+	return req, nil
 }
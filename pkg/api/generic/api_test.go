@@ -0,0 +1,53 @@
+package generic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Query", r.URL.RawQuery)
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewGenericAPIClient(&Config{BaseURL: server.URL})
+
+	resp, err := client.Do(context.Background(), &Request{
+		Method: "patch",
+		Path:   "/resource?a=1",
+		Query:  url.Values{"b": []string{"2"}},
+		Header: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+		Body:   strings.NewReader(`{"name":"value"}`),
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if !resp.IsError() || resp.StatusCode != http.StatusConflict {
+		t.Errorf("Do() status = %v, want %v", resp.StatusCode, http.StatusConflict)
+	}
+	if got := string(resp.Body); got != `{"name":"value"}` {
+		t.Errorf("Do() body = %v, want the request body to be echoed", got)
+	}
+	if got := resp.Header.Get("X-Method"); got != http.MethodPatch {
+		t.Errorf("Do() method = %v, want %v", got, http.MethodPatch)
+	}
+	if got := resp.Header.Get("X-Query"); got != "a=1&b=2" {
+		t.Errorf("Do() query = %v, want a=1&b=2", got)
+	}
+	if got := resp.Header.Get("X-Content-Type"); got != "application/merge-patch+json" {
+		t.Errorf("Do() content type = %v, want application/merge-patch+json", got)
+	}
+}
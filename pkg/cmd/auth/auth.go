@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+// NewAuthCommand creates a new command to manage the authentication of the current user
+func NewAuthCommand(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "auth",
+		Short:   f.Localizer.MustLocalize("auth.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("auth.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("auth.cmd.example"),
+		Args:    cobra.NoArgs,
+	}
+
+	cmd.AddCommand(token.NewTokenCommand(f))
+
+	return cmd
+}
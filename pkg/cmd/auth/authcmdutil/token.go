@@ -0,0 +1,32 @@
+package authcmdutil
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+)
+
+// LoadValidConfig loads the config with a valid access token.
+// The tokens are only refreshed when they are missing or about to expire.
+func LoadValidConfig(f *factory.Factory) (*config.Config, error) {
+	cfg, err := f.Config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	tkn := &token.Token{
+		AccessToken:  cfg.AccessToken,
+		RefreshToken: cfg.RefreshToken,
+		Logger:       f.Logger,
+	}
+	if !tkn.NeedsRefresh() {
+		return cfg, nil
+	}
+
+	// creating the connection refreshes the tokens and saves them to the config
+	if _, err = f.Connection(); err != nil {
+		return nil, err
+	}
+
+	return f.Config.Load()
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/auth/authcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO        *iostreams.IOStreams
+	localizer localize.Localizer
+	f         *factory.Factory
+
+	decode bool
+}
+
+// NewTokenCommand creates a new command to print the access token of the current user
+func NewTokenCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:        f.IOStreams,
+		localizer: f.Localizer,
+		f:         f,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "token",
+		Short:   f.Localizer.MustLocalize("token.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("token.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("token.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runToken(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.decode, "decode", false, f.Localizer.MustLocalize("token.flag.decode"))
+
+	return cmd
+}
+
+func runToken(opts *options) error {
+	cfg, err := authcmdutil.LoadValidConfig(opts.f)
+	if err != nil {
+		return err
+	}
+
+	if cfg.AccessToken == "" {
+		return opts.localizer.MustLocalizeError("token.log.info.tokenUnavailable")
+	}
+
+	if !opts.decode {
+		fmt.Fprintln(opts.IO.Out, cfg.AccessToken)
+		return nil
+	}
+
+	accessTkn, err := token.Parse(cfg.AccessToken)
+	if err != nil {
+		return err
+	}
+	claims, err := token.MapClaims(accessTkn)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	return dump.JSON(opts.IO.Out, data)
+}
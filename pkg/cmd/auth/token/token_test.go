@@ -0,0 +1,144 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/internal/mockutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tkn
+}
+
+func TestRunToken(t *testing.T) {
+	localizer, err := goi18n.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validToken := newTestToken(t, jwt.MapClaims{"preferred_username": "alice", "org_id": "12345", "exp": time.Now().Add(time.Hour).Unix()})
+	serviceAccountToken := newTestToken(t, jwt.MapClaims{"clientId": "srvc-acct-1", "rh-org-id": "67890", "exp": time.Now().Add(time.Hour).Unix()})
+	expiredToken := newTestToken(t, jwt.MapClaims{"preferred_username": "alice", "exp": time.Now().Add(-time.Hour).Unix()})
+
+	tests := []struct {
+		name         string
+		accessToken  string
+		refreshToken string
+		// refreshedToken is the access token saved by the connection, which fails to refresh the tokens when it is empty
+		refreshedToken string
+		decode         bool
+		wantToken      string
+		wantClaims     map[string]interface{}
+		wantErr        bool
+	}{
+		{
+			name:        "Should print a valid token",
+			accessToken: validToken,
+			wantToken:   validToken,
+		},
+		{
+			name:        "Should decode the claims of a user token",
+			accessToken: validToken,
+			decode:      true,
+			wantClaims:  map[string]interface{}{"preferred_username": "alice", "org_id": "12345"},
+		},
+		{
+			name:        "Should decode the claims of a service account token",
+			accessToken: serviceAccountToken,
+			decode:      true,
+			wantClaims:  map[string]interface{}{"clientId": "srvc-acct-1", "rh-org-id": "67890"},
+		},
+		{
+			name:           "Should print the refreshed token of an expired token",
+			accessToken:    expiredToken,
+			refreshToken:   "refresh",
+			refreshedToken: validToken,
+			wantToken:      validToken,
+		},
+		{
+			name:         "Should fail when an expired token cannot be refreshed",
+			accessToken:  expiredToken,
+			refreshToken: "expired",
+			wantErr:      true,
+		},
+		{
+			name:    "Should fail without a token",
+			wantErr: true,
+		},
+		{
+			name:        "Should fail to decode an invalid token",
+			accessToken: "not-a-token",
+			decode:      true,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{AccessToken: tt.accessToken, RefreshToken: tt.refreshToken}
+			var out bytes.Buffer
+			opts := &options{
+				IO:        &iostreams.IOStreams{Out: &out, ErrOut: io.Discard},
+				localizer: localizer,
+				decode:    tt.decode,
+				f: &factory.Factory{
+					Config: mockutil.NewConfigMock(cfg),
+					Logger: logger,
+					Connection: func() (connection.Connection, error) {
+						if tt.refreshedToken == "" {
+							return nil, errors.New("unable to refresh the tokens")
+						}
+						cfg.AccessToken = tt.refreshedToken
+						return &connection.ConnectionMock{}, nil
+					},
+				},
+			}
+
+			err := runToken(opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !tt.decode {
+				if got := strings.TrimSpace(out.String()); got != tt.wantToken {
+					t.Errorf("runToken() = %q, want %q", got, tt.wantToken)
+				}
+				return
+			}
+
+			var claims map[string]interface{}
+			if err = json.Unmarshal(out.Bytes(), &claims); err != nil {
+				t.Fatal(err)
+			}
+			for claim, want := range tt.wantClaims {
+				if claims[claim] != want {
+					t.Errorf("claim %v = %v, want %v", claim, claims[claim], want)
+				}
+			}
+		})
+	}
+}
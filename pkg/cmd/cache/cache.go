@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/cache/clear"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/cache/stats"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCommand creates a new command to manage the cache of the responses
+func NewCacheCommand(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cache",
+		Short:   f.Localizer.MustLocalize("cache.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("cache.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("cache.cmd.example"),
+		Args:    cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		stats.NewStatsCommand(f),
+		clear.NewClearCommand(f),
+	)
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package clear
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/cache"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	Logger    logging.Logger
+	Config    config.IConfig
+	localizer localize.Localizer
+
+	instance string
+}
+
+// NewClearCommand creates a new command to remove the cached responses
+func NewClearCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		Logger:    f.Logger,
+		Config:    f.Config,
+		localizer: f.Localizer,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "clear",
+		Short:   f.Localizer.MustLocalize("cache.clear.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("cache.clear.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("cache.clear.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runClear(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.instance, "instance", "", f.Localizer.MustLocalize("cache.clear.flag.instance.description"))
+
+	return cmd
+}
+
+func runClear(opts *options) error {
+	cfg, err := opts.Config.Load()
+	if err != nil {
+		return err
+	}
+
+	c, err := cache.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err = c.Clear(opts.instance); err != nil {
+		return err
+	}
+
+	if opts.instance == "" {
+		opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("cache.clear.log.info.successMessage"))
+	} else {
+		opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("cache.clear.log.info.instanceCleared", localize.NewEntry("Instance", opts.instance)))
+	}
+
+	return nil
+}
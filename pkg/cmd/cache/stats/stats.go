@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/cache"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO        *iostreams.IOStreams
+	Logger    logging.Logger
+	Config    config.IConfig
+	localizer localize.Localizer
+
+	outputFormat string
+}
+
+// statsRow describes the responses cached for an instance
+type statsRow struct {
+	Instance  string `json:"instance" yaml:"instance" header:"Instance"`
+	Entries   int    `json:"entries" yaml:"entries" header:"Entries"`
+	Expired   int    `json:"expired" yaml:"expired" header:"Expired"`
+	Size      string `json:"-" yaml:"-" header:"Size"`
+	SizeBytes int64  `json:"sizeBytes" yaml:"sizeBytes"`
+}
+
+// NewStatsCommand creates a new command to show the statistics of the cache
+func NewStatsCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:        f.IOStreams,
+		Logger:    f.Logger,
+		Config:    f.Config,
+		localizer: f.Localizer,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "stats",
+		Short:   f.Localizer.MustLocalize("cache.stats.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("cache.stats.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("cache.stats.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.outputFormat != "" {
+				if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			return runStats(opts)
+		},
+	}
+
+	flagutil.NewFlagSet(cmd, f.Localizer).AddOutput(&opts.outputFormat)
+
+	return cmd
+}
+
+func runStats(opts *options) error {
+	cfg, err := opts.Config.Load()
+	if err != nil {
+		return err
+	}
+
+	c, err := cache.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	rows := make([]statsRow, len(stats))
+	for i, instance := range stats {
+		rows[i] = statsRow{
+			Instance:  instance.Instance,
+			Entries:   instance.Entries,
+			Expired:   instance.Expired,
+			Size:      humanize.Bytes(uint64(instance.Size)),
+			SizeBytes: instance.Size,
+		}
+		size += instance.Size
+	}
+
+	if opts.outputFormat != dump.EmptyFormat {
+		return dump.Formatted(opts.IO.Out, opts.outputFormat, rows)
+	}
+
+	if len(rows) == 0 {
+		opts.Logger.Info(opts.localizer.MustLocalize("cache.stats.log.info.empty", localize.NewEntry("Dir", c.Dir)))
+		return nil
+	}
+
+	dump.Table(opts.IO.Out, rows)
+
+	maxSize := opts.localizer.MustLocalize("cache.stats.log.info.noLimit")
+	if c.MaxSize > 0 {
+		maxSize = humanize.Bytes(uint64(c.MaxSize))
+	}
+	opts.Logger.Info("")
+	opts.Logger.Info(opts.localizer.MustLocalize("cache.stats.log.info.total",
+		localize.NewEntry("Dir", c.Dir),
+		localize.NewEntry("Size", humanize.Bytes(uint64(size))),
+		localize.NewEntry("MaxSize", maxSize),
+	))
+
+	return nil
+}
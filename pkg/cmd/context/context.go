@@ -3,7 +3,11 @@ package context
 import (
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/create"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/delete"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/describe"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/list"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/rename"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/share"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/status"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/unset"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/use"
 
@@ -28,6 +32,11 @@ func NewContextCmd(f *factory.Factory) *cobra.Command {
 		create.NewCreateCommand(f),
 		delete.NewDeleteCommand(f),
 		unset.NewUnsetCommand(f),
+		rename.NewRenameCommand(f),
+		describe.NewDescribeCommand(f),
+		share.NewExportCommand(f),
+		share.NewImportCommand(f),
+		status.NewStatusCommand(f),
 	)
 	return cmd
 }
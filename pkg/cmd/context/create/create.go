@@ -2,8 +2,14 @@ package create
 
 import (
 	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
 
 	"github.com/apicurio/apicurio-cli/pkg/cmd/context/contextcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
@@ -14,6 +20,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// registryAPIPath is the path of the core Service Registry API
+const registryAPIPath = "/apis/registry/v2"
+
 type options struct {
 	IO             *iostreams.IOStreams
 	Logger         logging.Logger
@@ -22,7 +31,27 @@ type options struct {
 	Context        context.Context
 	ServiceContext servicecontext.IContext
 
-	name string
+	name        string
+	apiURL      string
+	authURL     string
+	clientID    string
+	tokenRef    string
+	registryURL string
+
+	authType             string
+	authUsername         string
+	authPasswordFile     string
+	authTokenFile        string
+	authIssuerURL        string
+	authClientID         string
+	authClientSecretFile string
+	authScopes           []string
+
+	caFile     string
+	clientCert string
+	clientKey  string
+	proxyURL   string
+	noProxy    string
 }
 
 // NewCreateCommand creates a new command to create contexts
@@ -56,6 +85,27 @@ func NewCreateCommand(f *factory.Factory) *cobra.Command {
 		"",
 		opts.localizer.MustLocalize("context.common.flag.name"),
 	)
+	flags.StringVar(&opts.apiURL, "api-gateway", "", opts.localizer.MustLocalize("context.create.flag.apiGateway.description"))
+	flags.StringVar(&opts.authURL, "auth-url", "", opts.localizer.MustLocalize("context.create.flag.authUrl.description"))
+	flags.StringVar(&opts.clientID, "client-id", "", opts.localizer.MustLocalize("context.create.flag.clientId.description"))
+	flags.StringVar(&opts.tokenRef, "token-ref", "", opts.localizer.MustLocalize("context.create.flag.tokenRef.description"))
+	flags.StringVar(&opts.registryURL, "registry-url", "", opts.localizer.MustLocalize("context.create.flag.registryUrl.description"))
+	flags.StringVar(&opts.authType, "auth-type", "", flagutil.FlagDescription(opts.localizer, "context.create.flag.authType.description", provider.Types...))
+	flags.StringVar(&opts.authUsername, "auth-username", "", opts.localizer.MustLocalize("context.create.flag.authUsername.description"))
+	flags.StringVar(&opts.authPasswordFile, "auth-password-file", "", opts.localizer.MustLocalize("context.create.flag.authPasswordFile.description", localize.NewEntry("EnvName", provider.PasswordEnvName)))
+	flags.StringVar(&opts.authTokenFile, "auth-token-file", "", opts.localizer.MustLocalize("context.create.flag.authTokenFile.description", localize.NewEntry("EnvName", provider.TokenEnvName)))
+	flags.StringVar(&opts.authIssuerURL, "auth-issuer-url", "", opts.localizer.MustLocalize("context.create.flag.authIssuerUrl.description"))
+	flags.StringVar(&opts.authClientID, "auth-client-id", "", opts.localizer.MustLocalize("context.create.flag.authClientId.description"))
+	flags.StringVar(&opts.authClientSecretFile, "auth-client-secret-file", "", opts.localizer.MustLocalize("context.create.flag.authClientSecretFile.description", localize.NewEntry("EnvName", provider.ClientSecretEnvName)))
+	flags.StringSliceVar(&opts.authScopes, "auth-scope", nil, opts.localizer.MustLocalize("context.create.flag.authScope.description"))
+
+	flags.StringVar(&opts.caFile, "ca-file", "", opts.localizer.MustLocalize("context.create.flag.caFile.description"))
+	flags.StringVar(&opts.clientCert, "client-cert", "", opts.localizer.MustLocalize("context.create.flag.clientCert.description"))
+	flags.StringVar(&opts.clientKey, "client-key", "", opts.localizer.MustLocalize("context.create.flag.clientKey.description"))
+	flags.StringVar(&opts.proxyURL, "proxy-url", "", opts.localizer.MustLocalize("context.create.flag.proxyUrl.description"))
+	flags.StringVar(&opts.noProxy, "no-proxy", "", opts.localizer.MustLocalize("context.create.flag.noProxy.description"))
+
+	flagutil.EnableStaticFlagCompletion(cmd, "auth-type", provider.Types)
 
 	return cmd
 
@@ -63,48 +113,148 @@ func NewCreateCommand(f *factory.Factory) *cobra.Command {
 
 func runCreate(opts *options) error {
 
-	svcContext, err := opts.ServiceContext.Load()
-	if err != nil {
-		return err
-	}
+	err := opts.ServiceContext.Update(func(svcContext *servicecontext.Context) (err error) {
+		profileValidator := &contextcmdutil.Validator{
+			Localizer:  opts.localizer,
+			SvcContext: svcContext,
+		}
 
-	profileValidator := &contextcmdutil.Validator{
-		Localizer:  opts.localizer,
-		SvcContext: svcContext,
-	}
+		svcContextsMap := svcContext.Contexts
 
-	svcContextsMap := svcContext.Contexts
+		if svcContextsMap == nil {
+			svcContextsMap = make(map[string]servicecontext.ServiceConfig)
+		}
 
-	if svcContextsMap == nil {
-		svcContextsMap = make(map[string]servicecontext.ServiceConfig)
-	}
+		err = profileValidator.ValidateName(opts.name)
+		if err != nil {
+			return err
+		}
+
+		err = profileValidator.ValidateNameIsAvailable(opts.name)
+		if err != nil {
+			return err
+		}
+
+		context, _ := contextutil.GetContext(svcContext, opts.localizer, opts.name)
+		if context != nil {
+			return opts.localizer.MustLocalizeError("context.create.log.alreadyExists", localize.NewEntry("Name", opts.name))
+		}
+
+		svcConfig := servicecontext.ServiceConfig{
+			APIURL:   opts.apiURL,
+			AuthURL:  opts.authURL,
+			ClientID: opts.clientID,
+			TokenRef: opts.tokenRef,
+			// the URL of the core registry API is also accepted
+			RegistryURL: strings.TrimSuffix(strings.TrimSuffix(opts.registryURL, "/"), registryAPIPath),
+		}
+		if svcConfig.Auth, err = authConfig(opts); err != nil {
+			return err
+		}
+		transportSettings := httputil.TransportSettings{
+			CAFile:     opts.caFile,
+			ClientCert: opts.clientCert,
+			ClientKey:  opts.clientKey,
+			ProxyURL:   opts.proxyURL,
+			NoProxy:    opts.noProxy,
+		}
+		if err = transportSettings.Resolve(); err != nil {
+			return opts.localizer.MustLocalizeError("context.create.error.invalidTransport", localize.NewEntry("Error", err))
+		}
+		svcConfig.CAFile = transportSettings.CAFile
+		svcConfig.ClientCert = transportSettings.ClientCert
+		svcConfig.ClientKey = transportSettings.ClientKey
+		svcConfig.ProxyURL = transportSettings.ProxyURL
+		svcConfig.NoProxy = transportSettings.NoProxy
+		for _, u := range []string{svcConfig.APIURL, svcConfig.AuthURL, svcConfig.RegistryURL, opts.authIssuerURL} {
+			if err = validateURL(u, opts.localizer); err != nil {
+				return err
+			}
+		}
+		// environments keep their own tokens unless they share a token reference
+		if svcConfig.HasEnvironment() && svcConfig.TokenRef == "" {
+			svcConfig.TokenRef = opts.name
+		}
 
-	err = profileValidator.ValidateName(opts.name)
+		svcContextsMap[opts.name] = svcConfig
+		svcContext.CurrentContext = opts.name
+
+		svcContext.Contexts = svcContextsMap
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	err = profileValidator.ValidateNameIsAvailable(opts.name)
-	if err != nil {
-		return err
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("context.create.log.successMessage", localize.NewEntry("Name", opts.name)))
+
+	return nil
+}
+
+func validateURL(value string, localizer localize.Localizer) error {
+	if value == "" {
+		return nil
 	}
 
-	context, _ := contextutil.GetContext(svcContext, opts.localizer, opts.name)
-	if context != nil {
-		return opts.localizer.MustLocalizeError("context.create.log.alreadyExists", localize.NewEntry("Name", opts.name))
+	u, err := url.ParseRequestURI(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return localizer.MustLocalizeError("context.create.error.invalidURL", localize.NewEntry("URL", value))
 	}
+	return nil
+}
 
-	svcContextsMap[opts.name] = servicecontext.ServiceConfig{}
-	svcContext.CurrentContext = opts.name
+// authConfig returns the authentication provider set with the flags, or nil.
+// The paths of the secret files are made absolute, so that the context can be used from any directory.
+func authConfig(opts *options) (*servicecontext.AuthConfig, error) {
+	if opts.authType == "" {
+		if opts.authUsername != "" || opts.authPasswordFile != "" || opts.authTokenFile != "" ||
+			opts.authIssuerURL != "" || opts.authClientID != "" || opts.authClientSecretFile != "" || len(opts.authScopes) > 0 {
+			return nil, opts.localizer.MustLocalizeError("context.create.error.authTypeRequired")
+		}
+		return nil, nil
+	}
 
-	svcContext.Contexts = svcContextsMap
+	if !provider.IsValidType(opts.authType) {
+		return nil, flagutil.InvalidValueError("auth-type", opts.authType, provider.Types...)
+	}
 
-	err = opts.ServiceContext.Save(svcContext)
-	if err != nil {
-		return err
+	required := map[string][]string{
+		provider.TypeBasic: {"auth-username"},
+		provider.TypeOIDC:  {"auth-issuer-url", "auth-client-id"},
+	}
+	values := map[string]string{
+		"auth-username":   opts.authUsername,
+		"auth-issuer-url": opts.authIssuerURL,
+		"auth-client-id":  opts.authClientID,
+	}
+	for _, flag := range required[opts.authType] {
+		if values[flag] == "" {
+			return nil, opts.localizer.MustLocalizeError("context.create.error.authFlagRequired", localize.NewEntry("Flag", flag), localize.NewEntry("Type", opts.authType))
+		}
 	}
 
-	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("context.create.log.successMessage", localize.NewEntry("Name", opts.name)))
+	cfg := &servicecontext.AuthConfig{
+		Type:      opts.authType,
+		Username:  opts.authUsername,
+		IssuerURL: opts.authIssuerURL,
+		ClientID:  opts.authClientID,
+		Scopes:    opts.authScopes,
+	}
+	for file, path := range map[*string]string{
+		&cfg.PasswordFile:     opts.authPasswordFile,
+		&cfg.TokenFile:        opts.authTokenFile,
+		&cfg.ClientSecretFile: opts.authClientSecretFile,
+	} {
+		if path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		*file = abs
+	}
 
-	return nil
+	return cfg, nil
 }
@@ -56,33 +56,30 @@ func NewDeleteCommand(f *factory.Factory) *cobra.Command {
 
 func runDelete(opts *options) error {
 
-	svcContext, err := opts.ServiceContext.Load()
-	if err != nil {
-		return err
-	}
+	err := opts.ServiceContext.Update(func(svcContext *servicecontext.Context) error {
+		currCtx := svcContext.CurrentContext
 
-	currCtx := svcContext.CurrentContext
+		if opts.name == "" || opts.name == currCtx {
 
-	if opts.name == "" || opts.name == currCtx {
-
-		if currCtx == "" {
-			return opts.localizer.MustLocalizeError("context.common.error.notSet")
-		}
+			if currCtx == "" {
+				return opts.localizer.MustLocalizeError("context.common.error.notSet")
+			}
 
-		opts.name = currCtx
+			opts.name = currCtx
 
-		svcContext.CurrentContext = ""
+			svcContext.CurrentContext = ""
 
-		opts.Logger.Info(opts.localizer.MustLocalize("context.delete.log.warning.currentUnset"))
-	}
+			opts.Logger.Info(opts.localizer.MustLocalize("context.delete.log.warning.currentUnset"))
+		}
 
-	if _, err = contextutil.GetContext(svcContext, opts.localizer, opts.name); err != nil {
-		return err
-	}
+		if _, err := contextutil.GetContext(svcContext, opts.localizer, opts.name); err != nil {
+			return err
+		}
 
-	delete(svcContext.Contexts, opts.name)
+		delete(svcContext.Contexts, opts.name)
 
-	err = opts.ServiceContext.Save(svcContext)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,156 @@
+package describe
+
+import (
+	"context"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	srsmgmtv1errors "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1/error"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO             *iostreams.IOStreams
+	Logger         logging.Logger
+	Connection     factory.ConnectionFunc
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+
+	name         string
+	outputFormat string
+}
+
+// contextDescription is the description of a context printed by the command
+type contextDescription struct {
+	Name            string                       `json:"name" yaml:"name"`
+	Current         bool                         `json:"current" yaml:"current"`
+	Services        servicecontext.ServiceConfig `json:"services" yaml:"services"`
+	ServiceRegistry *registryDescription         `json:"serviceRegistry,omitempty" yaml:"serviceRegistry,omitempty"`
+}
+
+// registryDescription describes the Service Registry instance set in a context
+type registryDescription struct {
+	ID          string                                  `json:"id" yaml:"id"`
+	Name        string                                  `json:"name,omitempty" yaml:"name,omitempty"`
+	Status      string                                  `json:"status,omitempty" yaml:"status,omitempty"`
+	RegistryURL string                                  `json:"registryUrl,omitempty" yaml:"registryUrl,omitempty"`
+	BrowserURL  string                                  `json:"browserUrl,omitempty" yaml:"browserUrl,omitempty"`
+	Endpoints   *registrycmdutil.CompatibilityEndpoints `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Error       string                                  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// NewDescribeCommand creates a new command to describe a context
+func NewDescribeCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:             f.IOStreams,
+		Logger:         f.Logger,
+		Connection:     f.Connection,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "describe [name]",
+		Short:   f.Localizer.MustLocalize("context.describe.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("context.describe.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("context.describe.cmd.example"),
+		Args:    cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.name = args[0]
+			}
+
+			if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+				return err
+			}
+
+			return runDescribe(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", dump.JSONFormat, flagutil.FlagDescription(f.Localizer, "flag.common.output.description", flagutil.ValidOutputFormats...))
+
+	flagutil.EnableOutputFlagCompletion(cmd)
+
+	return cmd
+}
+
+func runDescribe(opts *options) error {
+	svcContext, err := opts.ServiceContext.Load()
+	if err != nil {
+		return err
+	}
+
+	var svcConfig *servicecontext.ServiceConfig
+	if opts.name == "" {
+		svcConfig, err = contextutil.GetCurrentContext(svcContext, opts.localizer)
+		opts.name = svcContext.CurrentContext
+		if project := svcContext.Project; project != nil && project.Context != "" {
+			opts.name = project.Context
+		}
+	} else {
+		svcConfig, err = contextutil.GetContext(svcContext, opts.localizer, opts.name)
+	}
+	if err != nil {
+		return err
+	}
+
+	description := &contextDescription{
+		Name:     opts.name,
+		Current:  opts.name == svcContext.CurrentContext,
+		Services: *svcConfig,
+	}
+
+	// standalone registries are not managed by the management API
+	if svcConfig.ServiceRegistryID != "" && !svcConfig.IsStandalone() {
+		description.ServiceRegistry, err = describeRegistry(opts, svcConfig.ServiceRegistryID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return dump.Formatted(opts.IO.Out, opts.outputFormat, description)
+}
+
+// describeRegistry resolves the Service Registry instance of the context.
+// Failures to find the instance are part of the description instead of failing the command.
+func describeRegistry(opts *options, registryID string) (*registryDescription, error) {
+	conn, err := opts.Connection()
+	if err != nil {
+		return nil, err
+	}
+
+	description := &registryDescription{ID: registryID}
+
+	registry, httpRes, err := conn.API().ServiceRegistryMgmt().GetRegistry(opts.Context, registryID).Execute()
+	if httpRes != nil {
+		defer httpRes.Body.Close()
+	}
+	if srsmgmtv1errors.IsAPIError(err, srsmgmtv1errors.ERROR_2) {
+		description.Error = opts.localizer.MustLocalize("context.describe.error.registryNotFound")
+		return description, nil
+	}
+	if err != nil {
+		description.Error = err.Error()
+		return description, nil
+	}
+
+	description.Name = registry.GetName()
+	description.Status = string(registry.GetStatus())
+	description.RegistryURL = registry.GetRegistryUrl()
+	description.BrowserURL = registry.GetBrowserUrl()
+	if description.RegistryURL != "" {
+		description.Endpoints = registrycmdutil.GetCompatibilityEndpoints(description.RegistryURL)
+	}
+
+	return description, nil
+}
@@ -64,6 +64,12 @@ func runList(opts *options) error {
 
 	svcContextsMap := svcContext.Contexts
 
+	if opts.outputFormat == dump.EmptyFormat {
+		if err = printSource(opts, svcContext.Project); err != nil {
+			return err
+		}
+	}
+
 	if svcContextsMap == nil {
 		opts.Logger.Info(opts.localizer.MustLocalize("context.list.log.info.noContexts"))
 		return nil
@@ -87,3 +93,31 @@ func runList(opts *options) error {
 	return dump.Formatted(opts.IO.Out, opts.outputFormat, svcContextsMap)
 
 }
+
+// printSource prints the file the contexts are loaded from,
+// and the project context file which is active in the working directory
+func printSource(opts *options, project *servicecontext.ProjectConfig) error {
+	location, err := opts.ServiceContext.Location()
+	if err != nil {
+		return err
+	}
+
+	if servicecontext.HasCustomLocation() {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.list.log.info.customSource", localize.NewEntry("Path", location), localize.NewEntry("EnvName", servicecontext.ContextEnvName)))
+	} else {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.list.log.info.source", localize.NewEntry("Path", location)))
+	}
+
+	if project != nil {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.list.log.info.projectSource",
+			localize.NewEntry("Path", project.Path),
+			localize.NewEntry("Context", project.Context),
+			localize.NewEntry("RegistryID", project.ServiceRegistryID),
+			localize.NewEntry("Group", project.Group),
+			localize.NewEntry("Output", project.Output),
+		))
+	}
+
+	opts.Logger.Info()
+	return nil
+}
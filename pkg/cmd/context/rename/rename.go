@@ -0,0 +1,91 @@
+package rename
+
+import (
+	"context"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/contextcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO             *iostreams.IOStreams
+	Logger         logging.Logger
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+
+	oldName string
+	newName string
+}
+
+// NewRenameCommand creates a new command to rename a context
+func NewRenameCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:             f.IOStreams,
+		Logger:         f.Logger,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "rename <old-name> <new-name>",
+		Short:   f.Localizer.MustLocalize("context.rename.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("context.rename.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("context.rename.cmd.example"),
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.oldName = args[0]
+			opts.newName = args[1]
+
+			return runRename(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runRename(opts *options) error {
+	err := opts.ServiceContext.Update(func(svcContext *servicecontext.Context) error {
+		svcConfig, err := contextutil.GetContext(svcContext, opts.localizer, opts.oldName)
+		if err != nil {
+			return err
+		}
+
+		validator := &contextcmdutil.Validator{
+			Localizer:  opts.localizer,
+			SvcContext: svcContext,
+		}
+
+		if err = validator.ValidateName(opts.newName); err != nil {
+			return err
+		}
+
+		if err = validator.ValidateNameIsAvailable(opts.newName); err != nil {
+			return err
+		}
+
+		delete(svcContext.Contexts, opts.oldName)
+		svcContext.Contexts[opts.newName] = *svcConfig
+
+		if svcContext.CurrentContext == opts.oldName {
+			svcContext.CurrentContext = opts.newName
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("context.rename.log.info.success", localize.NewEntry("OldName", opts.oldName), localize.NewEntry("NewName", opts.newName)))
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	IO             *iostreams.IOStreams
+	Logger         logging.Logger
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+
+	name string
+}
+
+// NewExportCommand creates a new command to export service contexts
+func NewExportCommand(f *factory.Factory) *cobra.Command {
+	opts := &exportOptions{
+		IO:             f.IOStreams,
+		Logger:         f.Logger,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "export [name]",
+		Short:   f.Localizer.MustLocalize("context.export.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("context.export.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("context.export.cmd.example"),
+		Args:    cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.name = args[0]
+			}
+
+			return runExport(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runExport(opts *exportOptions) error {
+	svcContext, err := opts.ServiceContext.Load()
+	if err != nil {
+		return err
+	}
+
+	exported := &servicecontext.Context{
+		Contexts:       svcContext.Contexts,
+		CurrentContext: svcContext.CurrentContext,
+	}
+
+	// a single exported context becomes the current context of the importer
+	if opts.name != "" {
+		svcConfig, err := contextutil.GetContext(svcContext, opts.localizer, opts.name)
+		if err != nil {
+			return err
+		}
+		exported.Contexts = map[string]servicecontext.ServiceConfig{opts.name: *svcConfig}
+		exported.CurrentContext = opts.name
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(opts.IO.Out, string(data))
+	return err
+}
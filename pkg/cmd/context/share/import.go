@@ -0,0 +1,285 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/contextcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	IO             *iostreams.IOStreams
+	Logger         logging.Logger
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+
+	file    string
+	merge   bool
+	replace bool
+	force   bool
+}
+
+// NewImportCommand creates a new command to import service contexts
+func NewImportCommand(f *factory.Factory) *cobra.Command {
+	opts := &importOptions{
+		IO:             f.IOStreams,
+		Logger:         f.Logger,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "import <file>",
+		Short:   f.Localizer.MustLocalize("context.import.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("context.import.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("context.import.cmd.example"),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.file = args[0]
+
+			if opts.replace && !opts.force && !opts.IO.CanPrompt() {
+				return flagutil.RequiredWhenNonInteractiveError("yes")
+			}
+
+			return runImport(opts)
+		},
+	}
+
+	flags := contextcmdutil.NewFlagSet(cmd, f)
+	flags.BoolVar(&opts.merge, "merge", true, f.Localizer.MustLocalize("context.import.flag.merge.description"))
+	flags.BoolVar(&opts.replace, "replace", false, f.Localizer.MustLocalize("context.import.flag.replace.description"))
+	flags.AddYes(&opts.force)
+
+	cmd.MarkFlagsMutuallyExclusive("merge", "replace")
+
+	return cmd
+}
+
+func runImport(opts *importOptions) error {
+	imported, err := readContexts(opts)
+	if err != nil {
+		return err
+	}
+
+	validator := &contextcmdutil.Validator{Localizer: opts.localizer}
+	for name := range imported.Contexts {
+		if err = validator.ValidateName(name); err != nil {
+			return err
+		}
+	}
+
+	// the files and tokens of this machine are never chosen by a shared file
+	for _, name := range sortedContextNames(imported.Contexts) {
+		svcConfig, removed := withoutLocalReferences(imported.Contexts[name])
+		imported.Contexts[name] = svcConfig
+		if len(removed) > 0 {
+			opts.Logger.Info(icon.InfoPrefix(), opts.localizer.MustLocalize("context.import.log.info.localReferencesRemoved",
+				localize.NewEntry("Name", name),
+				localize.NewEntry("Fields", strings.Join(removed, ", ")),
+			))
+		}
+	}
+
+	if err = confirmEndpoints(opts, imported); err != nil {
+		return err
+	}
+
+	svcContext, err := opts.ServiceContext.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		svcContext, err = &servicecontext.Context{}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.replace && len(svcContext.Contexts) > 0 && !opts.force {
+		var shouldContinue bool
+		confirm := &survey.Confirm{
+			Message: opts.localizer.MustLocalize("context.import.input.confirmReplace.message", localize.NewEntry("Count", len(svcContext.Contexts))),
+		}
+		if err = survey.AskOne(confirm, &shouldContinue); err != nil {
+			return err
+		}
+
+		if !shouldContinue {
+			return errors.New("command stopped by user")
+		}
+	}
+
+	var overwritten []string
+	err = opts.ServiceContext.Update(func(svcContext *servicecontext.Context) error {
+		overwritten = importContexts(svcContext, imported, opts.replace)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range overwritten {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.import.log.info.overwritten", localize.NewEntry("Name", name)))
+	}
+
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("context.import.log.info.success", localize.NewEntry("Count", len(imported.Contexts))))
+
+	return nil
+}
+
+// readContexts reads the exported contexts from the file, or from standard input when the file is "-"
+func readContexts(opts *importOptions) (*servicecontext.Context, error) {
+	var data []byte
+	var err error
+	if opts.file == "-" {
+		data, err = io.ReadAll(opts.IO.In)
+	} else {
+		// #nosec G304
+		data, err = os.ReadFile(opts.file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var imported servicecontext.Context
+	if err = json.Unmarshal(data, &imported); err != nil {
+		return nil, opts.localizer.MustLocalizeError("context.import.error.invalidFile", localize.NewEntry("File", opts.file), localize.NewEntry("Error", err))
+	}
+
+	if len(imported.Contexts) == 0 {
+		return nil, opts.localizer.MustLocalizeError("context.import.error.noContexts", localize.NewEntry("File", opts.file))
+	}
+
+	return &imported, nil
+}
+
+// confirmEndpoints lists the servers the imported contexts send requests to, and asks to confirm them,
+// as the secrets of the user are sent to these servers
+func confirmEndpoints(opts *importOptions, imported *servicecontext.Context) error {
+	var endpoints []string
+	for _, name := range sortedContextNames(imported.Contexts) {
+		for _, endpoint := range contextEndpoints(imported.Contexts[name]) {
+			endpoints = append(endpoints, fmt.Sprintf("  %v: %v", name, endpoint))
+		}
+	}
+	if len(endpoints) == 0 || opts.force {
+		return nil
+	}
+	if !opts.IO.CanPrompt() {
+		return flagutil.RequiredWhenNonInteractiveError("yes")
+	}
+
+	opts.Logger.Info(opts.localizer.MustLocalize("context.import.log.info.endpoints"))
+	for _, endpoint := range endpoints {
+		opts.Logger.Info(endpoint)
+	}
+
+	var shouldContinue bool
+	confirm := &survey.Confirm{
+		Message: opts.localizer.MustLocalize("context.import.input.confirmEndpoints.message"),
+	}
+	if err := survey.AskOne(confirm, &shouldContinue); err != nil {
+		return err
+	}
+	if !shouldContinue {
+		return errors.New("command stopped by user")
+	}
+	return nil
+}
+
+// withoutLocalReferences removes the references to the files and stored tokens of this machine from the imported context,
+// and returns the names of the fields it removed.
+// A shared file could otherwise send the tokens of the user, or the content of any of their files, to its own servers.
+func withoutLocalReferences(svcConfig servicecontext.ServiceConfig) (servicecontext.ServiceConfig, []string) {
+	var removed []string
+	remove := func(field string, value *string) {
+		if *value != "" {
+			removed = append(removed, field)
+			*value = ""
+		}
+	}
+
+	remove("token_ref", &svcConfig.TokenRef)
+	remove("client_cert", &svcConfig.ClientCert)
+	remove("client_key", &svcConfig.ClientKey)
+	if svcConfig.Auth != nil {
+		auth := *svcConfig.Auth
+		remove("auth.password_file", &auth.PasswordFile)
+		remove("auth.token_file", &auth.TokenFile)
+		remove("auth.client_secret_file", &auth.ClientSecretFile)
+		svcConfig.Auth = &auth
+	}
+
+	return svcConfig, removed
+}
+
+// contextEndpoints returns the servers the context sends requests to, instead of the ones of the config file
+func contextEndpoints(svcConfig servicecontext.ServiceConfig) []string {
+	var endpoints []string
+	add := func(field string, value string) {
+		if value != "" {
+			endpoints = append(endpoints, field+" "+value)
+		}
+	}
+
+	add("api_url", svcConfig.APIURL)
+	add("auth_url", svcConfig.AuthURL)
+	add("registry_url", svcConfig.RegistryURL)
+	add("proxy_url", svcConfig.ProxyURL)
+	if svcConfig.Auth != nil {
+		add("auth.issuer_url", svcConfig.Auth.IssuerURL)
+	}
+
+	return endpoints
+}
+
+func sortedContextNames(contexts map[string]servicecontext.ServiceConfig) []string {
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// importContexts adds the imported contexts to svcContext and returns the names of the contexts it overwrote.
+// When replace is set, the existing contexts are removed first and the current context of the import is used.
+// Otherwise the current context is only taken from the import when none is set.
+func importContexts(svcContext *servicecontext.Context, imported *servicecontext.Context, replace bool) []string {
+	if replace || svcContext.Contexts == nil {
+		svcContext.Contexts = make(map[string]servicecontext.ServiceConfig, len(imported.Contexts))
+	}
+
+	var overwritten []string
+	for name, svcConfig := range imported.Contexts {
+		if _, ok := svcContext.Contexts[name]; ok {
+			overwritten = append(overwritten, name)
+		}
+		svcContext.Contexts[name] = svcConfig
+	}
+	sort.Strings(overwritten)
+
+	if _, ok := svcContext.Contexts[svcContext.CurrentContext]; replace || !ok {
+		svcContext.CurrentContext = ""
+		if _, ok := imported.Contexts[imported.CurrentContext]; ok {
+			svcContext.CurrentContext = imported.CurrentContext
+		}
+	}
+
+	return overwritten
+}
@@ -0,0 +1,121 @@
+package share
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+)
+
+func TestImportContexts(t *testing.T) {
+	existing := func() *servicecontext.Context {
+		return &servicecontext.Context{
+			CurrentContext: "dev",
+			Contexts: map[string]servicecontext.ServiceConfig{
+				"dev": {ServiceRegistryID: "dev-registry"},
+				"qa":  {ServiceRegistryID: "qa-registry"},
+			},
+		}
+	}
+	imported := &servicecontext.Context{
+		CurrentContext: "prod",
+		Contexts: map[string]servicecontext.ServiceConfig{
+			"qa":   {ServiceRegistryID: "new-qa-registry"},
+			"prod": {ServiceRegistryID: "prod-registry"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		svcContext      *servicecontext.Context
+		replace         bool
+		wantCurrent     string
+		wantContexts    []string
+		wantOverwritten []string
+	}{
+		{
+			name:            "merge keeps the current context",
+			svcContext:      existing(),
+			wantCurrent:     "dev",
+			wantContexts:    []string{"dev", "prod", "qa"},
+			wantOverwritten: []string{"qa"},
+		},
+		{
+			name:         "merge uses the imported current context when none is set",
+			svcContext:   &servicecontext.Context{},
+			wantCurrent:  "prod",
+			wantContexts: []string{"prod", "qa"},
+		},
+		{
+			name:         "replace removes the existing contexts",
+			svcContext:   existing(),
+			replace:      true,
+			wantCurrent:  "prod",
+			wantContexts: []string{"prod", "qa"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overwritten := importContexts(tt.svcContext, imported, tt.replace)
+
+			if !reflect.DeepEqual(overwritten, tt.wantOverwritten) {
+				t.Errorf("importContexts() overwritten = %v, want %v", overwritten, tt.wantOverwritten)
+			}
+			if tt.svcContext.CurrentContext != tt.wantCurrent {
+				t.Errorf("importContexts() current = %v, want %v", tt.svcContext.CurrentContext, tt.wantCurrent)
+			}
+			if got := sortedNames(tt.svcContext.Contexts); !reflect.DeepEqual(got, tt.wantContexts) {
+				t.Errorf("importContexts() contexts = %v, want %v", got, tt.wantContexts)
+			}
+			if got := tt.svcContext.Contexts["qa"].ServiceRegistryID; got != "new-qa-registry" {
+				t.Errorf("importContexts() qa registry = %v, want new-qa-registry", got)
+			}
+		})
+	}
+}
+
+func sortedNames(contexts map[string]servicecontext.ServiceConfig) []string {
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWithoutLocalReferences(t *testing.T) {
+	shared := servicecontext.ServiceConfig{
+		APIURL:      "https://api.example.com",
+		TokenRef:    "user@example.com",
+		RegistryURL: "https://registry.example.com",
+		ClientCert:  "/home/user/client.crt",
+		ClientKey:   "/home/user/client.key",
+		Auth: &servicecontext.AuthConfig{
+			Type:         "basic",
+			Username:     "user",
+			PasswordFile: "/home/user/.ssh/id_rsa",
+		},
+	}
+
+	svcConfig, removed := withoutLocalReferences(shared)
+
+	wantRemoved := []string{"token_ref", "client_cert", "client_key", "auth.password_file"}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("withoutLocalReferences() removed = %v, want %v", removed, wantRemoved)
+	}
+	if svcConfig.TokenRef != "" || svcConfig.ClientCert != "" || svcConfig.ClientKey != "" || svcConfig.Auth.PasswordFile != "" {
+		t.Errorf("withoutLocalReferences() = %+v, want no local references", svcConfig)
+	}
+	if svcConfig.Auth.Username != "user" || svcConfig.RegistryURL != shared.RegistryURL {
+		t.Errorf("withoutLocalReferences() = %+v, want the other settings kept", svcConfig)
+	}
+	if shared.Auth.PasswordFile == "" {
+		t.Error("withoutLocalReferences() modified the imported authentication")
+	}
+
+	wantEndpoints := []string{"api_url https://api.example.com", "registry_url https://registry.example.com"}
+	if endpoints := contextEndpoints(svcConfig); !reflect.DeepEqual(endpoints, wantEndpoints) {
+		t.Errorf("contextEndpoints() = %v, want %v", endpoints, wantEndpoints)
+	}
+}
@@ -0,0 +1,274 @@
+package status
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/context/contextcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/apicurio/apicurio-cli/pkg/shared/svcstatus"
+	srsmgmtv1errors "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1/error"
+	"github.com/spf13/cobra"
+)
+
+// Statuses reported when the status of the instance could not be retrieved
+const (
+	statusNotFound     = "not found"
+	statusUnauthorized = "unauthorized"
+	statusForbidden    = "forbidden"
+	statusError        = "error"
+	statusSkipped      = "skipped"
+)
+
+type options struct {
+	IO             *iostreams.IOStreams
+	Logger         logging.Logger
+	Connection     factory.ConnectionFunc
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+
+	name         string
+	all          bool
+	outputFormat string
+}
+
+// statusRow is the status of the Service Registry instance of a context
+type statusRow struct {
+	Context     string `json:"context" yaml:"context" header:"Context"`
+	RegistryID  string `json:"serviceregistryID" yaml:"serviceregistryID" header:"Registry ID"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty" header:"Name"`
+	Status      string `json:"status" yaml:"status" header:"Status"`
+	RegistryURL string `json:"registryUrl,omitempty" yaml:"registryUrl,omitempty" header:"Registry URL"`
+	Latency     string `json:"-" yaml:"-" header:"Latency"`
+	LatencyMs   int64  `json:"latencyMs" yaml:"latencyMs"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty" header:"Error"`
+}
+
+// NewStatusCommand creates a new command to check the services of contexts
+func NewStatusCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:             f.IOStreams,
+		Logger:         f.Logger,
+		Connection:     f.Connection,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   f.Localizer.MustLocalize("context.status.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("context.status.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("context.status.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.outputFormat != "" {
+				if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			return runStatus(opts)
+		},
+	}
+
+	flags := contextcmdutil.NewFlagSet(cmd, f)
+	flags.AddContextName(&opts.name)
+	flags.BoolVar(&opts.all, "all", false, f.Localizer.MustLocalize("context.status.flag.all.description"))
+	flags.AddOutput(&opts.outputFormat)
+
+	cmd.MarkFlagsMutuallyExclusive("name", "all")
+
+	return cmd
+}
+
+func runStatus(opts *options) error {
+	svcContext, err := opts.ServiceContext.Load()
+	if err != nil {
+		return err
+	}
+
+	contexts, err := selectContexts(opts, svcContext)
+	if err != nil {
+		return err
+	}
+
+	if len(contexts) == 0 {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.status.log.info.noRegistries"))
+		return nil
+	}
+
+	conn, err := opts.Connection()
+	if err != nil {
+		return err
+	}
+
+	// contexts of other environments need another login, so they are not checked
+	var activeEnv servicecontext.ServiceConfig
+	if active, ok := svcContext.Contexts[svcContext.ActiveName()]; ok {
+		activeEnv = active
+	}
+
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]statusRow, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		svcConfig := contexts[name]
+		if svcConfig.IsStandalone() {
+			rows[i] = statusRow{
+				Context:     name,
+				RegistryID:  svcConfig.ServiceRegistryID,
+				Status:      statusSkipped,
+				RegistryURL: svcConfig.RegistryURL,
+				Error:       opts.localizer.MustLocalize("context.status.error.standalone"),
+			}
+			continue
+		}
+		if !sameEnvironment(&svcConfig, &activeEnv) {
+			rows[i] = statusRow{
+				Context:    name,
+				RegistryID: svcConfig.ServiceRegistryID,
+				Status:     statusSkipped,
+				Error:      opts.localizer.MustLocalize("context.status.error.otherEnvironment"),
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, name string, registryID string) {
+			defer wg.Done()
+			rows[i] = checkRegistry(opts, conn.API(), name, registryID)
+		}(i, name, svcConfig.ServiceRegistryID)
+	}
+	wg.Wait()
+
+	if opts.outputFormat == dump.EmptyFormat {
+		dump.Table(opts.IO.Out, rows)
+	} else if err = dump.Formatted(opts.IO.Out, opts.outputFormat, rows); err != nil {
+		return err
+	}
+
+	// the command fails when a checked instance is not ready, so that it can be used in scripts
+	var checked, notReady int
+	for _, row := range rows {
+		if row.Status == statusSkipped {
+			continue
+		}
+		checked++
+		if row.Status != svcstatus.StatusReady {
+			notReady++
+		}
+	}
+	if notReady > 0 {
+		return opts.localizer.MustLocalizeError("context.status.error.notReady",
+			localize.NewEntry("Count", notReady), localize.NewEntry("Total", checked))
+	}
+
+	return nil
+}
+
+// selectContexts returns the contexts to check, by name.
+// Only the contexts with a Service Registry instance or a standalone registry are selected when --all is set.
+func selectContexts(opts *options, svcContext *servicecontext.Context) (map[string]servicecontext.ServiceConfig, error) {
+	if opts.all {
+		contexts := make(map[string]servicecontext.ServiceConfig)
+		for name, svcConfig := range svcContext.Contexts {
+			if svcConfig.ServiceRegistryID != "" || svcConfig.IsStandalone() {
+				contexts[name] = svcConfig
+			}
+		}
+		return contexts, nil
+	}
+
+	var svcConfig *servicecontext.ServiceConfig
+	var err error
+	name := opts.name
+	if name == "" {
+		svcConfig, err = contextutil.GetCurrentContext(svcContext, opts.localizer)
+		name = svcContext.ActiveName()
+	} else {
+		svcConfig, err = contextutil.GetContext(svcContext, opts.localizer, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if svcConfig.ServiceRegistryID == "" && !svcConfig.IsStandalone() {
+		return nil, opts.localizer.MustLocalizeError("context.common.error.noRegistryID")
+	}
+
+	return map[string]servicecontext.ServiceConfig{name: *svcConfig}, nil
+}
+
+// checkRegistry gets the Service Registry instance and reports its status and the latency of the request
+func checkRegistry(opts *options, conn api.API, name string, registryID string) statusRow {
+	row := statusRow{
+		Context:    name,
+		RegistryID: registryID,
+	}
+
+	start := time.Now()
+	registry, httpRes, err := conn.ServiceRegistryMgmt().GetRegistry(opts.Context, registryID).Execute()
+	latency := time.Since(start)
+	if httpRes != nil {
+		defer httpRes.Body.Close()
+	}
+
+	row.LatencyMs = latency.Milliseconds()
+	row.Latency = latency.Round(time.Millisecond).String()
+
+	if err != nil {
+		row.Status = errorStatus(err, httpRes)
+		row.Error = err.Error()
+		opts.Logger.Debug("Unable to get the Service Registry instance", registryID, "of context", name+":", err)
+		return row
+	}
+
+	row.Name = registry.GetName()
+	row.Status = svcstatus.ServiceStatus(registry.GetStatus())
+	row.RegistryURL = registry.GetRegistryUrl()
+
+	return row
+}
+
+// errorStatus returns the status reported for a failed request
+func errorStatus(err error, httpRes *http.Response) string {
+	if srsmgmtv1errors.IsAPIError(err, srsmgmtv1errors.ERROR_2) {
+		return statusNotFound
+	}
+
+	if httpRes != nil {
+		switch httpRes.StatusCode {
+		case http.StatusNotFound:
+			return statusNotFound
+		case http.StatusUnauthorized:
+			return statusUnauthorized
+		case http.StatusForbidden:
+			return statusForbidden
+		}
+	}
+
+	return statusError
+}
+
+// sameEnvironment reports whether both contexts connect to the same environment with the same login
+func sameEnvironment(a *servicecontext.ServiceConfig, b *servicecontext.ServiceConfig) bool {
+	return a.APIURL == b.APIURL && a.AuthURL == b.AuthURL && a.ClientID == b.ClientID && a.TokenRef == b.TokenRef
+}
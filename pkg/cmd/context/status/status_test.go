@@ -0,0 +1,223 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api/defaultapi"
+	"github.com/apicurio/apicurio-cli/pkg/shared/svcstatus"
+)
+
+// fakeContexts serves the contexts to the command
+type fakeContexts struct {
+	servicecontext.IContext
+	ctx *servicecontext.Context
+}
+
+func (c *fakeContexts) Load() (*servicecontext.Context, error) {
+	return c.ctx, nil
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		want       string
+	}{
+		{name: "Should report missing instances", statusCode: http.StatusNotFound, want: statusNotFound},
+		{name: "Should report expired sessions", statusCode: http.StatusUnauthorized, want: statusUnauthorized},
+		{name: "Should report denied access", statusCode: http.StatusForbidden, want: statusForbidden},
+		{name: "Should report other errors", statusCode: http.StatusBadGateway, want: statusError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorStatus(errors.New("request failed"), &http.Response{StatusCode: tt.statusCode})
+			if got != tt.want {
+				t.Errorf("errorStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := errorStatus(errors.New("connection refused"), nil); got != statusError {
+		t.Errorf("errorStatus() without response = %v, want %v", got, statusError)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	localizer, err := goi18n.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[string]string{"ready-registry": svcstatus.StatusReady, "new-registry": svcstatus.StatusProvisioning}
+	// the instances of all contexts are requested concurrently
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mu.Lock()
+		requested = append(requested, id)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		status, ok := statuses[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"code":"SRS-MGMT-2","reason":"not found"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"id":          id,
+			"name":        id,
+			"status":      status,
+			"registryUrl": "https://registry.example.com/" + id,
+		})
+	}))
+	defer srv.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	contexts := map[string]servicecontext.ServiceConfig{
+		"ready":       {ServiceRegistryID: "ready-registry"},
+		"new":         {ServiceRegistryID: "new-registry"},
+		"missing":     {ServiceRegistryID: "missing-registry"},
+		"standalone":  {RegistryURL: "http://localhost:8080"},
+		"staging":     {ServiceRegistryID: "ready-registry", APIURL: "https://api.staging.example.com"},
+		"no-registry": {},
+	}
+
+	tests := []struct {
+		name          string
+		current       string
+		contextName   string
+		all           bool
+		apiURL        string
+		wantStatuses  map[string]string
+		wantRequested int
+		wantErr       bool
+	}{
+		{
+			name:          "Should report a ready instance",
+			current:       "ready",
+			apiURL:        srv.URL,
+			wantStatuses:  map[string]string{"ready": svcstatus.StatusReady},
+			wantRequested: 1,
+		},
+		{
+			name:          "Should fail when the instance is not ready",
+			current:       "ready",
+			contextName:   "new",
+			apiURL:        srv.URL,
+			wantStatuses:  map[string]string{"new": svcstatus.StatusProvisioning},
+			wantRequested: 1,
+			wantErr:       true,
+		},
+		{
+			name:         "Should skip a standalone registry",
+			current:      "standalone",
+			apiURL:       srv.URL,
+			wantStatuses: map[string]string{"standalone": statusSkipped},
+		},
+		{
+			name:         "Should skip a context of another environment",
+			current:      "ready",
+			contextName:  "staging",
+			apiURL:       srv.URL,
+			wantStatuses: map[string]string{"staging": statusSkipped},
+		},
+		{
+			name:          "Should report an unreachable management API",
+			current:       "ready",
+			apiURL:        unreachable.URL,
+			wantStatuses:  map[string]string{"ready": statusError},
+			wantRequested: 0,
+			wantErr:       true,
+		},
+		{
+			name:    "Should check the instances of all contexts",
+			current: "ready",
+			all:     true,
+			apiURL:  srv.URL,
+			wantStatuses: map[string]string{
+				"ready":      svcstatus.StatusReady,
+				"new":        svcstatus.StatusProvisioning,
+				"missing":    statusNotFound,
+				"standalone": statusSkipped,
+				"staging":    statusSkipped,
+			},
+			wantRequested: 3,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			apiURL, _ := url.Parse(tt.apiURL)
+			var out bytes.Buffer
+			opts := &options{
+				IO:             &iostreams.IOStreams{Out: &out, ErrOut: io.Discard},
+				Logger:         logger,
+				localizer:      localizer,
+				Context:        context.Background(),
+				ServiceContext: &fakeContexts{ctx: &servicecontext.Context{Contexts: contexts, CurrentContext: tt.current}},
+				Connection: func() (connection.Connection, error) {
+					return &connection.ConnectionMock{
+						APIFunc: func() api.API {
+							return defaultapi.New(&api.Config{ApiURL: apiURL, HTTPClient: srv.Client(), Logger: logger})
+						},
+					}, nil
+				},
+				name:         tt.contextName,
+				all:          tt.all,
+				outputFormat: "json",
+			}
+
+			err := runStatus(opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var rows []statusRow
+			if err = json.Unmarshal(out.Bytes(), &rows); err != nil {
+				t.Fatalf("runStatus() printed invalid JSON: %v\n%s", err, out.String())
+			}
+			got := make(map[string]string, len(rows))
+			for _, row := range rows {
+				got[row.Context] = row.Status
+				if (row.Status == statusSkipped || row.Status == statusNotFound || row.Status == statusError) && row.Error == "" {
+					t.Errorf("row %v with status %v has no error", row.Context, row.Status)
+				}
+			}
+			if len(got) != len(tt.wantStatuses) {
+				t.Errorf("runStatus() statuses = %v, want %v", got, tt.wantStatuses)
+			}
+			for name, want := range tt.wantStatuses {
+				if got[name] != want {
+					t.Errorf("status of %v = %q, want %q", name, got[name], want)
+				}
+			}
+			if len(requested) != tt.wantRequested {
+				t.Errorf("runStatus() requested %v, want %v requests", requested, tt.wantRequested)
+			}
+		})
+	}
+}
@@ -59,47 +59,45 @@ func NewUnsetCommand(f *factory.Factory) *cobra.Command {
 
 func runUnset(opts *options) error {
 
-	svcContext, err := opts.f.ServiceContext.Load()
-	if err != nil {
-		return err
-	}
-
-	var svcConfig *servicecontext.ServiceConfig
-	var ctxName string
-
-	if opts.name == "" {
-		svcConfig, err = contextutil.GetCurrentContext(svcContext, opts.f.Localizer)
-		if err != nil {
-			return err
-		}
-		ctxName = svcContext.CurrentContext
-	} else {
-		svcConfig, err = contextutil.GetContext(svcContext, opts.f.Localizer, opts.name)
-		if err != nil {
-			return err
+	err := opts.f.ServiceContext.Update(func(svcContext *servicecontext.Context) error {
+		var svcConfig *servicecontext.ServiceConfig
+		var ctxName string
+		var err error
+
+		if opts.name == "" {
+			svcConfig, err = contextutil.GetCurrentContext(svcContext, opts.f.Localizer)
+			if err != nil {
+				return err
+			}
+			ctxName = svcContext.CurrentContext
+		} else {
+			svcConfig, err = contextutil.GetContext(svcContext, opts.f.Localizer, opts.name)
+			if err != nil {
+				return err
+			}
+			ctxName = opts.name
 		}
-		ctxName = opts.name
-	}
 
-	if flagutil.StringInSlice(servicespec.KafkaServiceName, opts.services) {
-		svcConfig.KafkaID = ""
-	}
+		if flagutil.StringInSlice(servicespec.KafkaServiceName, opts.services) {
+			svcConfig.KafkaID = ""
+		}
 
-	if flagutil.StringInSlice(servicespec.ServiceRegistryServiceName, opts.services) {
-		svcConfig.ServiceRegistryID = ""
-	}
+		if flagutil.StringInSlice(servicespec.ServiceRegistryServiceName, opts.services) {
+			svcConfig.ServiceRegistryID = ""
+		}
 
-	if flagutil.StringInSlice(servicespec.ConnectorServiceName, opts.services) {
-		svcConfig.ConnectorID = ""
-	}
+		if flagutil.StringInSlice(servicespec.ConnectorServiceName, opts.services) {
+			svcConfig.ConnectorID = ""
+		}
 
-	if flagutil.StringInSlice(servicespec.NamespaceServiceName, opts.services) {
-		svcConfig.NamespaceID = ""
-	}
+		if flagutil.StringInSlice(servicespec.NamespaceServiceName, opts.services) {
+			svcConfig.NamespaceID = ""
+		}
 
-	svcContext.Contexts[ctxName] = *svcConfig
+		svcContext.Contexts[ctxName] = *svcConfig
 
-	err = opts.f.ServiceContext.Save(svcContext)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -63,32 +63,42 @@ func NewUseCommand(f *factory.Factory) *cobra.Command {
 
 func runUse(opts *options) error {
 
-	svcContext, err := opts.ServiceContext.Load()
-	if err != nil {
-		return err
-	}
-
 	if opts.name == "" {
-		opts.name, err = runInteractivePrompt(opts, svcContext)
+		svcContext, err := opts.ServiceContext.Load()
 		if err != nil {
 			return err
 		}
-	}
 
-	_, err = contextutil.GetContext(svcContext, opts.localizer, opts.name)
-	if err != nil {
-		return err
+		opts.name, err = runInteractivePrompt(opts, svcContext)
+		if err != nil {
+			return err
+		}
 	}
 
-	svcContext.CurrentContext = opts.name
+	var svcConfig *servicecontext.ServiceConfig
+	err := opts.ServiceContext.Update(func(svcContext *servicecontext.Context) (err error) {
+		svcConfig, err = contextutil.GetContext(svcContext, opts.localizer, opts.name)
+		if err != nil {
+			return err
+		}
 
-	err = opts.ServiceContext.Save(svcContext)
+		svcContext.CurrentContext = opts.name
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
 	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("context.use.successMessage", localize.NewEntry("Name", opts.name)))
 
+	if svcConfig.HasEnvironment() {
+		opts.Logger.Info(opts.localizer.MustLocalize("context.use.log.info.environment",
+			localize.NewEntry("APIURL", svcConfig.APIURL),
+			localize.NewEntry("AuthURL", svcConfig.AuthURL),
+			localize.NewEntry("TokenRef", svcConfig.TokenRef),
+		))
+	}
+
 	return nil
 }
 
@@ -2,19 +2,25 @@ package login
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
+	"sync"
 
 	"github.com/apicurio/apicurio-cli/pkg/core/auth/login"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
 
 	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/credentials"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/spinner"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
 	"github.com/apicurio/apicurio-cli/pkg/shared/connection/kcconnection"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 
@@ -42,13 +48,21 @@ var authURLAliases = map[string]string{
 	"stage":      build.StagingAuthURL,
 }
 
+// Environment variables with the credentials of a service account,
+// used to log in with the client credentials grant when the flags are not set
+const (
+	ClientIDEnvName     = "APICR_CLIENT_ID"
+	ClientSecretEnvName = "APICR_CLIENT_SECRET"
+)
+
 type options struct {
-	Config     config.IConfig
-	Logger     logging.Logger
-	Connection factory.ConnectionFunc
-	IO         *iostreams.IOStreams
-	localizer  localize.Localizer
-	Context    context.Context
+	Config         config.IConfig
+	ServiceContext servicecontext.IContext
+	Logger         logging.Logger
+	Connection     factory.ConnectionFunc
+	IO             *iostreams.IOStreams
+	localizer      localize.Localizer
+	Context        context.Context
 
 	url                   string
 	authURL               string
@@ -57,17 +71,30 @@ type options struct {
 	insecureSkipTLSVerify bool
 	printURL              bool
 	offlineToken          string
+	clientSecretFile      string
+	device                bool
+	credentialStore       string
+	clientSecret          string
+	caFile                string
+	clientCert            string
+	clientKey             string
+	proxyURL              string
+	noProxy               string
+
+	// transport sends the login requests, with the TLS and proxy settings of the flags and of the active context
+	transport http.RoundTripper
 }
 
 // NewLoginCmd gets the command that's log the user in
 func NewLoginCmd(f *factory.Factory) *cobra.Command {
 	opts := &options{
-		Config:     f.Config,
-		Connection: f.Connection,
-		Logger:     f.Logger,
-		IO:         f.IOStreams,
-		localizer:  f.Localizer,
-		Context:    f.Context,
+		Config:         f.Config,
+		ServiceContext: f.ServiceContext,
+		Connection:     f.Connection,
+		Logger:         f.Logger,
+		IO:             f.IOStreams,
+		localizer:      f.Localizer,
+		Context:        f.Context,
 	}
 
 	cmd := &cobra.Command{
@@ -77,12 +104,26 @@ func NewLoginCmd(f *factory.Factory) *cobra.Command {
 		Example: opts.localizer.MustLocalize("login.cmd.example"),
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			applyEnvironmentDefaults(cmd, opts)
+
+			if err := applyTransportSettings(cmd, opts); err != nil {
+				return err
+			}
+
+			if err := applyClientCredentials(cmd, opts); err != nil {
+				return err
+			}
+
+			if err := credentials.ValidateStoreName(opts.credentialStore); err != nil {
+				return opts.localizer.MustLocalizeError("login.error.invalidCredentialStore", localize.NewEntry("Error", err))
+			}
+
 			if opts.offlineToken != "" && opts.clientID == build.DefaultClientID {
 				opts.clientID = build.DefaultOfflineTokenClientID
 			}
 
-			if opts.IO.IsSSHSession() && opts.offlineToken == "" {
-				opts.Logger.Debug(opts.localizer.MustLocalize("login.log.debug.sshLoginDetected", localize.NewEntry("OfflineTokenURL", build.OfflineTokenURL)))
+			if opts.IO.IsSSHSession() && opts.offlineToken == "" && opts.clientSecret == "" && !opts.device {
+				opts.Logger.Info(opts.localizer.MustLocalize("login.log.info.sshLoginDetected", localize.NewEntry("OfflineTokenURL", build.OfflineTokenURL)))
 			}
 
 			return runLogin(opts)
@@ -96,6 +137,22 @@ func NewLoginCmd(f *factory.Factory) *cobra.Command {
 	cmd.Flags().BoolVar(&opts.printURL, "print-sso-url", false, opts.localizer.MustLocalize("login.flag.printSsoUrl"))
 	cmd.Flags().StringArrayVar(&opts.scopes, "scope", kcconnection.DefaultScopes, opts.localizer.MustLocalize("login.flag.scope"))
 	cmd.Flags().StringVarP(&opts.offlineToken, "token", "t", "", opts.localizer.MustLocalize("login.flag.token", localize.NewEntry("OfflineTokenURL", build.OfflineTokenURL)))
+	cmd.Flags().StringVar(&opts.caFile, "ca-file", "", opts.localizer.MustLocalize("login.flag.caFile"))
+	cmd.Flags().StringVar(&opts.clientCert, "client-cert", "", opts.localizer.MustLocalize("login.flag.clientCert"))
+	cmd.Flags().StringVar(&opts.clientKey, "client-key", "", opts.localizer.MustLocalize("login.flag.clientKey"))
+	cmd.Flags().StringVar(&opts.proxyURL, "proxy-url", "", opts.localizer.MustLocalize("login.flag.proxyUrl"))
+	cmd.Flags().StringVar(&opts.noProxy, "no-proxy", "", opts.localizer.MustLocalize("login.flag.noProxy"))
+	cmd.Flags().StringVar(&opts.clientSecretFile, "client-secret-file", "", opts.localizer.MustLocalize("login.flag.clientSecretFile", localize.NewEntry("EnvName", ClientSecretEnvName)))
+
+	cmd.Flags().BoolVar(&opts.device, "device", false, opts.localizer.MustLocalize("login.flag.device"))
+	cmd.Flags().StringVar(&opts.credentialStore, "credential-store", "", opts.localizer.MustLocalize("login.flag.credentialStore",
+		localize.NewEntry("EnvName", credentials.StoreEnvName),
+		localize.NewEntry("PassphraseEnvName", credentials.PassphraseEnvName),
+		localize.NewEntry("KeyEnvName", credentials.KeyEnvName),
+	))
+
+	cmd.MarkFlagsMutuallyExclusive("token", "client-secret-file", "device")
+	cmd.MarkFlagsMutuallyExclusive("device", "print-sso-url")
 
 	return cmd
 }
@@ -117,10 +174,56 @@ func runLogin(opts *options) (err error) {
 	spinner := spinner.New(opts.IO.ErrOut, opts.localizer)
 	spinner.SetLocalizedSuffix("login.log.info.loggingIn")
 	spinner.Start()
-	if opts.offlineToken == "" {
-		tr := createTransport(opts.insecureSkipTLSVerify)
+	// the spinner is stopped once, as the device authorization grant stops it before printing the user code
+	var stopSpinnerOnce sync.Once
+	stopSpinner := func() { stopSpinnerOnce.Do(spinner.Stop) }
+	var grantToken *oauth2.Token
+	if opts.clientSecret != "" {
+		loginExec := &login.ClientCredentialsGrant{
+			HTTPClient:   &http.Client{Transport: opts.transport},
+			Logger:       opts.Logger,
+			ClientID:     opts.clientID,
+			ClientSecret: opts.clientSecret,
+			Scopes:       opts.scopes,
+		}
+
+		ctx, cancel := context.WithTimeout(opts.Context, build.DefaultLoginTimeout)
+		defer cancel()
+
+		if grantToken, err = loginExec.Execute(ctx, authURL); err != nil {
+			stopSpinner()
+			opts.Logger.Info()
+			if errors.Is(err, context.DeadlineExceeded) {
+				return opts.localizer.MustLocalizeError("login.error.context.deadline.exceeded")
+			}
+
+			return err
+		}
+	} else if opts.device {
+		loginExec := &login.DeviceAuthorizationGrant{
+			HTTPClient: &http.Client{Transport: opts.transport},
+			Logger:     opts.Logger,
+			IO:         opts.IO,
+			Localizer:  opts.localizer,
+			ClientID:   opts.clientID,
+			Scopes:     opts.scopes,
+
+			OnAuthorization: stopSpinner,
+		}
+
+		// the login lasts until the device code expires, as the user may need time to switch to another device
+		if grantToken, err = loginExec.Execute(opts.Context, authURL); err != nil {
+			stopSpinner()
+			opts.Logger.Info()
+			if errors.Is(err, context.DeadlineExceeded) {
+				return opts.localizer.MustLocalizeError("login.error.deviceCodeExpired")
+			}
+
+			return err
+		}
+	} else if opts.offlineToken == "" {
 		httpClient := oauth2.NewClient(opts.Context, nil)
-		httpClient.Transport = tr
+		httpClient.Transport = opts.transport
 
 		loginExec := &login.AuthorizationCodeGrant{
 			HTTPClient: httpClient,
@@ -143,7 +246,7 @@ func runLogin(opts *options) (err error) {
 		defer cancel()
 
 		if err = loginExec.Execute(ctx, ssoCfg, gatewayURL.String()); err != nil {
-			spinner.Stop()
+			stopSpinner()
 			opts.Logger.Info()
 			if errors.Is(err, context.DeadlineExceeded) {
 				return opts.localizer.MustLocalizeError("login.error.context.deadline.exceeded")
@@ -152,40 +255,155 @@ func runLogin(opts *options) (err error) {
 			return err
 		}
 	}
-	spinner.Stop()
+	stopSpinner()
+
+	profile := flagutil.SelectedProfile()
+	// logging in to a profile does not make it the current profile
+	var otherProfile bool
+	err = opts.Config.Update(func(cfg *config.Config) error {
+		otherProfile = config.ActiveProfile(cfg, profile) != config.ActiveProfile(cfg, "")
+		if opts.offlineToken != "" {
+			cfg.RefreshToken = opts.offlineToken
+		}
 
-	cfg, err := opts.Config.Load()
+		cfg.APIUrl = gatewayURL.String()
+		cfg.Insecure = opts.insecureSkipTLSVerify
+		cfg.CAFile = opts.caFile
+		cfg.ClientCert = opts.clientCert
+		cfg.ClientKey = opts.clientKey
+		cfg.ProxyURL = opts.proxyURL
+		cfg.NoProxy = opts.noProxy
+		cfg.ClientID = opts.clientID
+		cfg.AuthURL = opts.authURL
+		cfg.Scopes = opts.scopes
+		cfg.ClientSecret = opts.clientSecret
+		if opts.credentialStore != "" {
+			cfg.CredentialStore = opts.credentialStore
+		}
+		// Reset access token on login to avoid reusing previous users valid token
+		cfg.AccessToken = ""
+		if grantToken != nil {
+			cfg.AccessToken = grantToken.AccessToken
+			cfg.RefreshToken = grantToken.RefreshToken
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if opts.offlineToken != "" {
-		cfg.RefreshToken = opts.offlineToken
+	opts.Logger.Info()
+	if profile == "" {
+		opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("login.log.info.loginSuccess"))
+		return nil
 	}
 
-	cfg.APIUrl = gatewayURL.String()
-	cfg.Insecure = opts.insecureSkipTLSVerify
-	cfg.ClientID = opts.clientID
-	cfg.AuthURL = opts.authURL
-	cfg.Scopes = opts.scopes
-	// Reset access token on login to avoid reusing previous users valid token
-	cfg.AccessToken = ""
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("login.log.info.profileLoginSuccess", localize.NewEntry("Profile", profile)))
+	if otherProfile {
+		opts.Logger.Info(opts.localizer.MustLocalize("login.log.info.useProfile", localize.NewEntry("Profile", profile)))
+	}
 
-	if err = opts.Config.Save(cfg); err != nil {
-		return err
+	return nil
+}
+
+// applyEnvironmentDefaults uses the endpoints of the active context environment
+// for the flags which were not set, so that logging in keeps the environment of the context
+func applyEnvironmentDefaults(cmd *cobra.Command, opts *options) {
+	_, env := servicecontext.ActiveEnvironment(opts.ServiceContext)
+	if env == nil {
+		return
 	}
 
-	opts.Logger.Info()
-	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("login.log.info.loginSuccess"))
+	flags := cmd.Flags()
+	if env.APIURL != "" && !flags.Changed("api-gateway") {
+		opts.url = env.APIURL
+	}
+	if env.AuthURL != "" && !flags.Changed("auth-url") {
+		opts.authURL = env.AuthURL
+	}
+	if env.ClientID != "" && !flags.Changed("client-id") {
+		opts.clientID = env.ClientID
+	}
+}
+
+// applyClientCredentials reads the secret of the service account from the --client-secret-file flag
+// or from the environment, along with the client ID when the --client-id flag is not set.
+// An offline token takes precedence over the secret in the environment.
+func applyClientCredentials(cmd *cobra.Command, opts *options) error {
+	switch {
+	case opts.clientSecretFile != "":
+		// #nosec G304
+		data, err := os.ReadFile(opts.clientSecretFile)
+		if err != nil {
+			return opts.localizer.MustLocalizeError("login.error.readClientSecretFile", localize.NewEntry("Path", opts.clientSecretFile), localize.NewEntry("Error", err))
+		}
+		opts.clientSecret = strings.TrimSpace(string(data))
+		if opts.clientSecret == "" {
+			return opts.localizer.MustLocalizeError("login.error.emptyClientSecret", localize.NewEntry("Path", opts.clientSecretFile))
+		}
+	case opts.offlineToken == "":
+		opts.clientSecret = os.Getenv(ClientSecretEnvName)
+	}
+
+	if opts.clientSecret == "" {
+		return nil
+	}
+
+	if clientID := os.Getenv(ClientIDEnvName); clientID != "" && !cmd.Flags().Changed("client-id") {
+		opts.clientID = clientID
+	}
+
+	if opts.clientID == build.DefaultClientID {
+		return opts.localizer.MustLocalizeError("login.error.clientIdRequired", localize.NewEntry("EnvName", ClientIDEnvName))
+	}
 
 	return nil
 }
 
-func createTransport(insecure bool) *http.Transport {
-	// #nosec 402
-	return &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+// applyTransportSettings validates the TLS and proxy settings of the flags, which are saved with the login,
+// and creates the transport of the login requests. The settings of the active context are used for the flags which were not set.
+func applyTransportSettings(cmd *cobra.Command, opts *options) error {
+	settings := httputil.TransportSettings{
+		Insecure:   opts.insecureSkipTLSVerify,
+		CAFile:     opts.caFile,
+		ClientCert: opts.clientCert,
+		ClientKey:  opts.clientKey,
+		ProxyURL:   opts.proxyURL,
+		NoProxy:    opts.noProxy,
 	}
+	if err := settings.Resolve(); err != nil {
+		return opts.localizer.MustLocalizeError("login.error.invalidTransport", localize.NewEntry("Error", err))
+	}
+	opts.caFile = settings.CAFile
+	opts.clientCert = settings.ClientCert
+	opts.clientKey = settings.ClientKey
+
+	if svcContext, err := opts.ServiceContext.Load(); err == nil {
+		if svcConfig, ok := svcContext.Contexts[svcContext.ActiveName()]; ok {
+			flags := cmd.Flags()
+			if svcConfig.CAFile != "" && !flags.Changed("ca-file") {
+				settings.CAFile = svcConfig.CAFile
+			}
+			if svcConfig.ClientCert != "" && !flags.Changed("client-cert") && !flags.Changed("client-key") {
+				settings.ClientCert = svcConfig.ClientCert
+				settings.ClientKey = svcConfig.ClientKey
+			}
+			if svcConfig.ProxyURL != "" && !flags.Changed("proxy-url") {
+				settings.ProxyURL = svcConfig.ProxyURL
+			}
+			if svcConfig.NoProxy != "" && !flags.Changed("no-proxy") {
+				settings.NoProxy = svcConfig.NoProxy
+			}
+		}
+	}
+
+	transport, err := settings.Transport()
+	if err != nil {
+		return opts.localizer.MustLocalizeError("login.error.invalidTransport", localize.NewEntry("Error", err))
+	}
+	opts.transport = &httputil.TracingRoundTripper{Proxied: transport}
+
+	return nil
 }
 
 func getURLFromAlias(urlOrAlias string, urlAliasMap map[string]string, localizer localize.Localizer) (u *url.URL, err error) {
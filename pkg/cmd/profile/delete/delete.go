@@ -0,0 +1,76 @@
+package delete
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/profilecmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	Logger    logging.Logger
+	Config    config.IConfig
+	localizer localize.Localizer
+
+	name string
+}
+
+// NewDeleteCommand creates a new command to delete a login profile
+func NewDeleteCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		Logger: f.Logger,
+		// the profiles are deleted from the config file, without the selected profile applied
+		Config:    config.NewFile(),
+		localizer: f.Localizer,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "delete <name>",
+		Short:             f.Localizer.MustLocalize("profile.delete.cmd.shortDescription"),
+		Long:              f.Localizer.MustLocalize("profile.delete.cmd.longDescription"),
+		Example:           f.Localizer.MustLocalize("profile.delete.cmd.example"),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: profilecmdutil.CompleteNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+
+			return runDelete(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runDelete(opts *options) error {
+	if opts.name == config.DefaultProfileName {
+		return opts.localizer.MustLocalizeError("profile.delete.error.defaultProfile")
+	}
+
+	// the credentials of the profile are erased from the credential store when the config is saved without it
+	var wasCurrent bool
+	err := opts.Config.Update(func(cfg *config.Config) error {
+		if _, ok := cfg.Profiles[opts.name]; !ok {
+			return opts.localizer.MustLocalizeError("profile.common.error.notFound", localize.NewEntry("Name", opts.name))
+		}
+
+		delete(cfg.Profiles, opts.name)
+		if cfg.CurrentProfile == opts.name {
+			cfg.CurrentProfile = ""
+			wasCurrent = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if wasCurrent {
+		opts.Logger.Info(opts.localizer.MustLocalize("profile.delete.log.info.currentUnset"))
+	}
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("profile.delete.log.info.successMessage", localize.NewEntry("Name", opts.name)))
+
+	return nil
+}
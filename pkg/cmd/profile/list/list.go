@@ -0,0 +1,94 @@
+package list
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/profilecmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO        *iostreams.IOStreams
+	Config    config.IConfig
+	localizer localize.Localizer
+
+	outputFormat string
+}
+
+// profileRow describes a login profile
+type profileRow struct {
+	Name       string `json:"name" yaml:"name" header:"Name"`
+	Current    bool   `json:"current" yaml:"current" header:"Current"`
+	Username   string `json:"username,omitempty" yaml:"username,omitempty" header:"Username"`
+	APIGateway string `json:"apiGateway,omitempty" yaml:"apiGateway,omitempty" header:"API Gateway"`
+	AuthURL    string `json:"authURL,omitempty" yaml:"authURL,omitempty" header:"Auth URL"`
+}
+
+// NewListCommand creates a new command to list the login profiles
+func NewListCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO: f.IOStreams,
+		// the profiles are read from the config file, without the selected profile applied
+		Config:    config.NewFile(),
+		localizer: f.Localizer,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   f.Localizer.MustLocalize("profile.list.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("profile.list.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("profile.list.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.outputFormat != "" {
+				if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			return runList(opts)
+		},
+	}
+
+	flagutil.NewFlagSet(cmd, f.Localizer).AddOutput(&opts.outputFormat)
+
+	return cmd
+}
+
+func runList(opts *options) error {
+	cfg, err := opts.Config.Load()
+	if err != nil {
+		return err
+	}
+
+	active := config.ActiveProfile(cfg, flagutil.SelectedProfile())
+
+	names := profilecmdutil.Names(cfg)
+	rows := make([]profileRow, len(names))
+	for i, name := range names {
+		profile := config.ProfileOf(cfg)
+		if name != config.DefaultProfileName {
+			profile = cfg.Profiles[name]
+		}
+
+		rows[i] = profileRow{
+			Name:       name,
+			Current:    config.ActiveProfile(cfg, name) == active,
+			APIGateway: profile.APIUrl,
+			AuthURL:    profile.AuthURL,
+		}
+		rows[i].Username, _ = token.GetUsername(profile.AccessToken)
+	}
+
+	if opts.outputFormat == dump.EmptyFormat {
+		dump.Table(opts.IO.Out, rows)
+		return nil
+	}
+
+	return dump.Formatted(opts.IO.Out, opts.outputFormat, rows)
+}
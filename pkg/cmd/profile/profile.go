@@ -0,0 +1,28 @@
+package profile
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/delete"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/list"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/use"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+// NewProfileCommand creates a new command to manage login profiles
+func NewProfileCommand(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Short:   f.Localizer.MustLocalize("profile.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("profile.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("profile.cmd.example"),
+		Args:    cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		list.NewListCommand(f),
+		use.NewUseCommand(f),
+		delete.NewDeleteCommand(f),
+	)
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package profilecmdutil
+
+import (
+	"sort"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/spf13/cobra"
+)
+
+// Names returns the names of the login profiles of the config, starting with the default profile
+func Names(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Profiles)+1)
+	for name := range cfg.Profiles {
+		if name != config.DefaultProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return append([]string{config.DefaultProfileName}, names...)
+}
+
+// Exists reports whether the config has a login profile with the name
+func Exists(cfg *config.Config, name string) bool {
+	if name == config.DefaultProfileName {
+		return true
+	}
+	_, ok := cfg.Profiles[name]
+	return ok
+}
+
+// CompleteNames completes the names of the login profiles of the config file
+func CompleteNames(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.NewFile().Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return Names(cfg), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,63 @@
+package use
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/profilecmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	Logger    logging.Logger
+	Config    config.IConfig
+	localizer localize.Localizer
+
+	name string
+}
+
+// NewUseCommand creates a new command to set the current login profile
+func NewUseCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		Logger: f.Logger,
+		// the current profile is set in the config file, without the selected profile applied
+		Config:    config.NewFile(),
+		localizer: f.Localizer,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "use <name>",
+		Short:             f.Localizer.MustLocalize("profile.use.cmd.shortDescription"),
+		Long:              f.Localizer.MustLocalize("profile.use.cmd.longDescription"),
+		Example:           f.Localizer.MustLocalize("profile.use.cmd.example"),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: profilecmdutil.CompleteNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+
+			return runUse(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runUse(opts *options) error {
+	err := opts.Config.Update(func(cfg *config.Config) error {
+		if !profilecmdutil.Exists(cfg, opts.name) {
+			return opts.localizer.MustLocalizeError("profile.common.error.notFound", localize.NewEntry("Name", opts.name))
+		}
+
+		cfg.CurrentProfile = config.ActiveProfile(&config.Config{}, opts.name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	opts.Logger.Info(icon.SuccessPrefix(), opts.localizer.MustLocalize("profile.use.log.info.successMessage", localize.NewEntry("Name", opts.name)))
+
+	return nil
+}
@@ -45,6 +45,7 @@ func NewArtifactsCommand(f *factory.Factory) *cobra.Command {
 		state.NewSetStateCommand(f),
 		owner.NewGetCommand(f),
 		owner.NewSetCommand(f),
+		owner.NewOwnerCommand(f),
 		types.NewGetTypesCommand(f),
 	)
 
@@ -0,0 +1,265 @@
+package owner
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+	"github.com/spf13/cobra"
+)
+
+// number of artifacts fetched for each search request
+const searchPageSize int32 = 100
+
+const (
+	transferResultTransferred = "transferred"
+	transferResultFailed      = "failed"
+)
+
+// transferRow is the outcome of transferring the ownership of a single artifact
+type transferRow struct {
+	Group         string `json:"group" header:"Group"`
+	ArtifactID    string `json:"artifactId" header:"Artifact ID"`
+	PreviousOwner string `json:"previousOwner" header:"Previous owner"`
+	NewOwner      string `json:"newOwner" header:"New owner"`
+	Result        string `json:"result" header:"Result"`
+	Error         string `json:"error,omitempty"`
+}
+
+// transferReport is the audit record of an ownership transfer
+type transferReport struct {
+	RegistryID  string        `json:"registryId"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Group       string        `json:"group,omitempty"`
+	Timestamp   string        `json:"timestamp"`
+	Transferred int           `json:"transferred"`
+	Failed      int           `json:"failed"`
+	Artifacts   []transferRow `json:"artifacts"`
+}
+
+type transferOptions struct {
+	from         string
+	to           string
+	group        string
+	registryID   string
+	outputFormat string
+	force        bool
+
+	f *factory.Factory
+}
+
+// NewOwnerCommand creates a new command to manage the ownership of artifacts
+func NewOwnerCommand(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner",
+		Short: f.Localizer.MustLocalize("artifact.cmd.owner.description.short"),
+		Long:  f.Localizer.MustLocalize("artifact.cmd.owner.description.long"),
+		Args:  cobra.MinimumNArgs(1),
+	}
+
+	cmd.AddCommand(
+		NewTransferCommand(f),
+	)
+
+	return cmd
+}
+
+// NewTransferCommand creates a new command to transfer the ownership of all artifacts of a principal
+func NewTransferCommand(f *factory.Factory) *cobra.Command {
+
+	opts := &transferOptions{
+		f: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "transfer",
+		Short:   f.Localizer.MustLocalize("artifact.cmd.owner.transfer.description.short"),
+		Long:    f.Localizer.MustLocalize("artifact.cmd.owner.transfer.description.long"),
+		Example: f.Localizer.MustLocalize("artifact.cmd.owner.transfer.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			if util.OutputFormatFromString(opts.outputFormat) == util.UnknownOutputFormat {
+				return opts.f.Localizer.MustLocalizeError("artifact.common.error.invalidOutputFormat")
+			}
+
+			if opts.from == opts.to {
+				return opts.f.Localizer.MustLocalizeError("artifact.cmd.owner.transfer.error.sameOwner")
+			}
+
+			if !opts.f.IOStreams.CanPrompt() && !opts.force {
+				return flagutil.RequiredWhenNonInteractiveError("yes")
+			}
+
+			if opts.registryID != "" {
+				return runTransfer(opts)
+			}
+
+			registryInstance, err := contextutil.GetCurrentRegistryInstance(f)
+			if err != nil {
+				return err
+			}
+
+			opts.registryID = registryInstance.GetId()
+
+			return runTransfer(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.from, "from", "", f.Localizer.MustLocalize("artifact.cmd.owner.transfer.flag.from.description"))
+	cmd.Flags().StringVar(&opts.to, "to", "", f.Localizer.MustLocalize("artifact.cmd.owner.transfer.flag.to.description"))
+	cmd.Flags().StringVarP(&opts.group, "group", "g", "", f.Localizer.MustLocalize("artifact.cmd.owner.transfer.flag.group.description"))
+	cmd.Flags().StringVar(&opts.registryID, "instance-id", "", f.Localizer.MustLocalize("registry.common.flag.instance.id"))
+	cmd.Flags().BoolVarP(&opts.force, "yes", "y", false, f.Localizer.MustLocalize("artifact.cmd.owner.transfer.flag.yes.description"))
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "table", f.Localizer.MustLocalize("artifact.common.message.output.format"))
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	flagutil.EnableOutputFlagCompletion(cmd)
+
+	return cmd
+}
+
+// nolint:funlen
+func runTransfer(opts *transferOptions) error {
+	format := util.OutputFormatFromString(opts.outputFormat)
+
+	conn, err := opts.f.Connection()
+	if err != nil {
+		return err
+	}
+
+	dataAPI, _, err := conn.API().ServiceRegistryInstance(opts.registryID)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := findOwnedArtifacts(opts, dataAPI)
+	if err != nil {
+		return err
+	}
+
+	if len(artifacts) == 0 {
+		opts.f.Logger.Info(opts.f.Localizer.MustLocalize("artifact.cmd.owner.transfer.log.info.noArtifacts", localize.NewEntry("Owner", opts.from)))
+		return nil
+	}
+
+	rows := make([]transferRow, len(artifacts))
+	for i := range artifacts {
+		rows[i] = transferRow{
+			Group:         groupOf(&artifacts[i]),
+			ArtifactID:    artifacts[i].GetId(),
+			PreviousOwner: opts.from,
+			NewOwner:      opts.to,
+		}
+	}
+
+	if !opts.force {
+		opts.f.Logger.Info(opts.f.Localizer.MustLocalize("artifact.cmd.owner.transfer.log.info.artifactsFound",
+			localize.NewEntry("Count", len(rows)), localize.NewEntry("Owner", opts.from)))
+		_ = util.Dump(opts.f.IOStreams.ErrOut, util.TableOutputFormat, rows, nil)
+
+		var shouldContinue bool
+		confirm := &survey.Confirm{
+			Message: opts.f.Localizer.MustLocalize("artifact.cmd.owner.transfer.input.confirm.message", localize.NewEntry("Owner", opts.to)),
+		}
+		if err = survey.AskOne(confirm, &shouldContinue); err != nil {
+			return err
+		}
+
+		if !shouldContinue {
+			return errors.New("command stopped by user")
+		}
+	}
+
+	report := transferReport{
+		RegistryID: opts.registryID,
+		From:       opts.from,
+		To:         opts.to,
+		Group:      opts.group,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	newOwner := opts.to
+	for i := range rows {
+		row := &rows[i]
+		_, err = dataAPI.MetadataApi.UpdateArtifactOwner(opts.f.Context, row.Group, row.ArtifactID).
+			ArtifactOwner(registryinstanceclient.ArtifactOwner{Owner: &newOwner}).
+			Execute()
+		if err != nil {
+			report.Failed++
+			row.Result = transferResultFailed
+			row.Error = registrycmdutil.TransformInstanceError(err).Error()
+			opts.f.Logger.Debug("Failed to transfer ownership of", row.Group+"/"+row.ArtifactID+":", row.Error)
+			continue
+		}
+		report.Transferred++
+		row.Result = transferResultTransferred
+	}
+	report.Artifacts = rows
+
+	if err = util.Dump(opts.f.IOStreams.Out, format, rows, report); err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return opts.f.Localizer.MustLocalizeError("artifact.cmd.owner.transfer.error.failed",
+			localize.NewEntry("Failed", report.Failed), localize.NewEntry("Total", len(rows)))
+	}
+
+	opts.f.Logger.Info(icon.SuccessPrefix(), opts.f.Localizer.MustLocalize("artifact.cmd.owner.transfer.log.info.success",
+		localize.NewEntry("Count", report.Transferred), localize.NewEntry("From", opts.from), localize.NewEntry("To", opts.to)))
+
+	return nil
+}
+
+// findOwnedArtifacts searches all pages of artifacts for the ones owned by the source principal.
+// Service Registry records the owner of an artifact as its creator, so this also
+// matches artifacts whose ownership has previously been transferred to the principal.
+func findOwnedArtifacts(opts *transferOptions, dataAPI *registryinstanceclient.APIClient) ([]registryinstanceclient.SearchedArtifact, error) {
+	owned := []registryinstanceclient.SearchedArtifact{}
+
+	var offset int32
+	for {
+		request := dataAPI.SearchApi.SearchArtifacts(opts.f.Context).
+			Offset(offset).
+			Limit(searchPageSize).
+			Orderby(registryinstanceclient.SORTBY_CREATED_ON).
+			Order(registryinstanceclient.SORTORDER_ASC)
+
+		if opts.group != "" {
+			request = request.Group(opts.group)
+		}
+
+		response, _, err := request.Execute()
+		if err != nil {
+			return nil, registrycmdutil.TransformInstanceError(err)
+		}
+
+		for _, a := range response.GetArtifacts() {
+			if a.GetCreatedBy() == opts.from {
+				owned = append(owned, a)
+			}
+		}
+
+		offset += int32(len(response.GetArtifacts()))
+		if len(response.GetArtifacts()) < int(searchPageSize) || offset >= response.GetCount() {
+			return owned, nil
+		}
+	}
+}
+
+func groupOf(artifact *registryinstanceclient.SearchedArtifact) string {
+	if group := artifact.GetGroupId(); group != "" {
+		return group
+	}
+	return registrycmdutil.DefaultArtifactGroup
+}
@@ -0,0 +1,251 @@
+package owner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api/defaultapi"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+)
+
+// fakeRegistry serves the artifact search and owner endpoints of a Service Registry instance
+type fakeRegistry struct {
+	artifacts []map[string]interface{}
+	// failOwner is the ID of the artifact whose owner cannot be updated
+	failOwner string
+
+	mu       sync.Mutex
+	searches []string
+	owners   map[string]string
+}
+
+func newFakeRegistry(count int, createdBy func(i int) string) *fakeRegistry {
+	r := &fakeRegistry{owners: map[string]string{}}
+	for i := 0; i < count; i++ {
+		group := "default"
+		if i%2 == 1 {
+			group = "team"
+		}
+		r.artifacts = append(r.artifacts, map[string]interface{}{
+			"id":        fmt.Sprintf("artifact-%03d", i),
+			"groupId":   group,
+			"createdBy": createdBy(i),
+			"createdOn": "2023-01-01T00:00:00Z",
+			"type":      "AVRO",
+			"state":     "ENABLED",
+		})
+	}
+	return r
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/search/artifacts"):
+		r.searches = append(r.searches, req.URL.RawQuery)
+		query := req.URL.Query()
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		var matching []map[string]interface{}
+		for _, a := range r.artifacts {
+			if group := query.Get("group"); group == "" || a["groupId"] == group {
+				matching = append(matching, a)
+			}
+		}
+		page := []map[string]interface{}{}
+		for i := offset; i < offset+limit && i < len(matching); i++ {
+			page = append(page, matching[i])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"artifacts": page, "count": len(matching)})
+	case req.Method == http.MethodPut && strings.HasSuffix(req.URL.Path, "/owner"):
+		parts := strings.Split(req.URL.Path, "/")
+		id := parts[len(parts)-2]
+		if id == r.failOwner {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"error_code":403,"message":"not allowed"}`)
+			return
+		}
+		var owner struct {
+			Owner string `json:"owner"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&owner)
+		r.owners[id] = owner.Owner
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func newTestFactory(t *testing.T, srv *httptest.Server, out io.Writer) *factory.Factory {
+	localizer, err := goi18n.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &factory.Factory{
+		IOStreams: &iostreams.IOStreams{Out: out, ErrOut: io.Discard},
+		Localizer: localizer,
+		Logger:    logger,
+		Context:   context.Background(),
+		Connection: func() (connection.Connection, error) {
+			return &connection.ConnectionMock{
+				APIFunc: func() api.API {
+					return defaultapi.New(&api.Config{
+						HTTPClient:   srv.Client(),
+						Logger:       logger,
+						RegistryURL:  srv.URL,
+						AuthProvider: &provider.None{},
+					})
+				},
+			}, nil
+		},
+	}
+}
+
+func TestFindOwnedArtifacts(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		group        string
+		wantOwned    int
+		wantSearches int
+	}{
+		{name: "Should find the artifacts created by the principal", count: 10, wantOwned: 4, wantSearches: 1},
+		{name: "Should search all pages", count: 250, wantOwned: 84, wantSearches: 3},
+		{name: "Should stop after a full last page", count: 200, wantOwned: 67, wantSearches: 2},
+		{name: "Should only search the group", count: 10, group: "team", wantOwned: 2, wantSearches: 1},
+		{name: "Should find no artifacts", count: 0, wantOwned: 0, wantSearches: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newFakeRegistry(tt.count, func(i int) string {
+				if i%3 == 0 {
+					return "alice"
+				}
+				return "bob"
+			})
+			srv := httptest.NewServer(registry)
+			defer srv.Close()
+
+			opts := &transferOptions{from: "alice", to: "carol", group: tt.group, f: newTestFactory(t, srv, io.Discard)}
+			conn, _ := opts.f.Connection()
+			dataAPI, _, err := conn.API().ServiceRegistryInstance("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			owned, err := findOwnedArtifacts(opts, dataAPI)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(owned) != tt.wantOwned {
+				t.Errorf("findOwnedArtifacts() found %v artifacts, want %v", len(owned), tt.wantOwned)
+			}
+			for _, a := range owned {
+				if a.GetCreatedBy() != "alice" {
+					t.Errorf("findOwnedArtifacts() found %v created by %v", a.GetId(), a.GetCreatedBy())
+				}
+				if tt.group != "" && a.GetGroupId() != tt.group {
+					t.Errorf("findOwnedArtifacts() found %v in group %v", a.GetId(), a.GetGroupId())
+				}
+			}
+			if len(registry.searches) != tt.wantSearches {
+				t.Errorf("findOwnedArtifacts() sent %v searches %v, want %v", len(registry.searches), registry.searches, tt.wantSearches)
+			}
+		})
+	}
+}
+
+func TestRunTransferReport(t *testing.T) {
+	registry := newFakeRegistry(6, func(i int) string {
+		if i < 3 {
+			return "alice"
+		}
+		return "bob"
+	})
+	registry.failOwner = "artifact-001"
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	opts := &transferOptions{
+		from:         "alice",
+		to:           "carol",
+		registryID:   "registry",
+		outputFormat: "json",
+		force:        true,
+		f:            newTestFactory(t, srv, &out),
+	}
+
+	if err := runTransfer(opts); err == nil {
+		t.Error("runTransfer() should fail when an artifact could not be transferred")
+	}
+
+	var report transferReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("the audit report is not valid JSON: %v\n%s", err, out.String())
+	}
+	if report.RegistryID != "registry" || report.From != "alice" || report.To != "carol" || report.Timestamp == "" {
+		t.Errorf("report = %+v, want the registry, the principals and the time of the transfer", report)
+	}
+	if report.Transferred != 2 || report.Failed != 1 || len(report.Artifacts) != 3 {
+		t.Fatalf("report = %+v, want 2 transferred and 1 failed artifacts", report)
+	}
+
+	for _, row := range report.Artifacts {
+		wantResult := transferResultTransferred
+		if row.ArtifactID == registry.failOwner {
+			wantResult = transferResultFailed
+			if row.Error == "" {
+				t.Errorf("row %v has no error", row.ArtifactID)
+			}
+		} else if registry.owners[row.ArtifactID] != "carol" {
+			t.Errorf("owner of %v = %q, want carol", row.ArtifactID, registry.owners[row.ArtifactID])
+		}
+		if row.Result != wantResult || row.PreviousOwner != "alice" || row.NewOwner != "carol" {
+			t.Errorf("row = %+v, want result %v from alice to carol", row, wantResult)
+		}
+	}
+	if len(registry.owners) != 2 {
+		t.Errorf("owners of %v were updated, want only the artifacts created by alice", registry.owners)
+	}
+}
+
+func TestRunTransferNoArtifacts(t *testing.T) {
+	registry := newFakeRegistry(3, func(int) string { return "bob" })
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	opts := &transferOptions{from: "alice", to: "carol", outputFormat: "json", force: true, f: newTestFactory(t, srv, &out)}
+	if err := runTransfer(opts); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 || len(registry.owners) != 0 {
+		t.Errorf("runTransfer() printed %q and updated %v, want nothing to be transferred", out.String(), registry.owners)
+	}
+}
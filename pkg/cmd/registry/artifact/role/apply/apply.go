@@ -0,0 +1,296 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// resultRow is the outcome of applying a single change of the plan
+type resultRow struct {
+	Principal string `json:"principal" header:"Principal"`
+	Type      string `json:"type" header:"Type"`
+	Action    string `json:"action" header:"Action"`
+	Role      string `json:"role,omitempty" header:"Role"`
+	Result    string `json:"result" header:"Result"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	resultApplied = "applied"
+	resultFailed  = "failed"
+)
+
+type options struct {
+	file         string
+	registryID   string
+	outputFormat string
+	dryRun       bool
+	prune        bool
+	force        bool
+
+	IO             *iostreams.IOStreams
+	Connection     factory.ConnectionFunc
+	Logger         logging.Logger
+	localizer      localize.Localizer
+	Context        context.Context
+	ServiceContext servicecontext.IContext
+}
+
+// NewApplyCommand creates a new command for reconciling the roles of many principals from a file
+func NewApplyCommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:             f.IOStreams,
+		Connection:     f.Connection,
+		Logger:         f.Logger,
+		localizer:      f.Localizer,
+		Context:        f.Context,
+		ServiceContext: f.ServiceContext,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "apply",
+		Short:   f.Localizer.MustLocalize("registry.role.cmd.apply.shortDescription"),
+		Long:    f.Localizer.MustLocalize("registry.role.cmd.apply.longDescription"),
+		Example: f.Localizer.MustLocalize("registry.role.cmd.apply.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if util.OutputFormatFromString(opts.outputFormat) == util.UnknownOutputFormat {
+				return opts.localizer.MustLocalizeError("artifact.common.error.invalidOutputFormat")
+			}
+
+			if !opts.IO.CanPrompt() && !opts.force && !opts.dryRun {
+				return flagutil.RequiredWhenNonInteractiveError("yes")
+			}
+
+			if opts.registryID != "" {
+				return runApply(opts)
+			}
+
+			registryInstance, err := contextutil.GetCurrentRegistryInstance(f)
+			if err != nil {
+				return err
+			}
+
+			opts.registryID = registryInstance.GetId()
+			return runApply(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", opts.localizer.MustLocalize("registry.role.cmd.apply.flag.file.description"))
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, opts.localizer.MustLocalize("registry.role.cmd.apply.flag.dryRun.description"))
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, opts.localizer.MustLocalize("registry.role.cmd.apply.flag.prune.description"))
+	cmd.Flags().BoolVarP(&opts.force, "yes", "y", false, opts.localizer.MustLocalize("registry.role.cmd.apply.flag.yes.description"))
+	cmd.Flags().StringVar(&opts.registryID, "instance-id", "", opts.localizer.MustLocalize("registry.common.flag.instance.id"))
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "table", opts.localizer.MustLocalize("artifact.common.message.output.format"))
+
+	_ = cmd.MarkFlagRequired("file")
+	flagutil.EnableOutputFlagCompletion(cmd)
+
+	return cmd
+}
+
+// nolint:funlen
+func runApply(opts *options) error {
+	format := util.OutputFormatFromString(opts.outputFormat)
+
+	opts.Logger.Info(opts.localizer.MustLocalize("artifact.common.message.opening.file", localize.NewEntry("FileName", opts.file)))
+	// #nosec G304
+	file, err := os.Open(opts.file)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	desired, err := parseRoles(opts.file, file)
+	if err != nil {
+		return err
+	}
+
+	// an empty file would revoke all the roles of the instance
+	if opts.prune && len(desired) == 0 {
+		return opts.localizer.MustLocalizeError("registry.role.cmd.apply.error.pruneEmptyFile", localize.NewEntry("FileName", opts.file))
+	}
+
+	conn, err := opts.Connection()
+	if err != nil {
+		return err
+	}
+
+	principalTypes, err := resolvePrincipals(opts, conn.API(), desired)
+	if err != nil {
+		return err
+	}
+
+	dataAPI, _, err := conn.API().ServiceRegistryInstance(opts.registryID)
+	if err != nil {
+		return err
+	}
+
+	mappings, _, err := dataAPI.AdminApi.ListRoleMappings(opts.Context).Execute()
+	if err != nil {
+		return registrycmdutil.TransformInstanceError(err)
+	}
+
+	plan, kept := computePlan(desired, mappings, principalTypes, opts.prune)
+	if len(kept) > 0 {
+		opts.Logger.Info(opts.localizer.MustLocalize("registry.role.cmd.apply.log.info.unlistedKept",
+			localize.NewEntry("Count", len(kept)), localize.NewEntry("Principals", strings.Join(kept, ", "))))
+	}
+	if len(plan) == 0 {
+		opts.Logger.Info(opts.localizer.MustLocalize("registry.role.cmd.apply.log.info.noChanges"))
+		return nil
+	}
+
+	if opts.dryRun {
+		return util.Dump(opts.IO.Out, format, plan, nil)
+	}
+
+	if format == util.TableOutputFormat || !opts.force {
+		opts.Logger.Info(opts.localizer.MustLocalize("registry.role.cmd.apply.log.info.plan", localize.NewEntry("Registry", opts.registryID)))
+		dumpPlan(opts, plan)
+	}
+
+	if !opts.force {
+		var shouldContinue bool
+		confirm := &survey.Confirm{
+			Message: opts.localizer.MustLocalize("registry.role.cmd.apply.input.confirm.message"),
+		}
+		if err = survey.AskOne(confirm, &shouldContinue); err != nil {
+			return err
+		}
+
+		if !shouldContinue {
+			return errors.New("command stopped by user")
+		}
+	}
+
+	results := make([]resultRow, 0, len(plan))
+	var failed int
+	for _, item := range plan {
+		result := resultRow{
+			Principal: item.Principal,
+			Type:      item.Type,
+			Action:    item.Action,
+			Role:      item.Role,
+			Result:    resultApplied,
+		}
+
+		if err = applyPlanItem(opts, dataAPI.AdminApi, item); err != nil {
+			failed++
+			result.Result = resultFailed
+			result.Error = registrycmdutil.TransformInstanceError(err).Error()
+			opts.Logger.Debug("Failed to", item.Action, "role for", item.Principal+":", result.Error)
+		}
+
+		results = append(results, result)
+	}
+
+	if err = util.Dump(opts.IO.Out, format, results, nil); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return opts.localizer.MustLocalizeError("registry.role.cmd.apply.error.failed",
+			localize.NewEntry("Failed", failed), localize.NewEntry("Total", len(plan)))
+	}
+
+	opts.Logger.Info(opts.localizer.MustLocalize("registry.role.cmd.apply.log.info.success"))
+
+	return nil
+}
+
+// dumpPlan prints the plan as a table to the error stream, so it does not mix with
+// machine readable output of the results
+func dumpPlan(opts *options, plan []planItem) {
+	_ = util.Dump(opts.IO.ErrOut, util.TableOutputFormat, plan, nil)
+}
+
+func applyPlanItem(opts *options, admin registryinstanceclient.AdminApi, item planItem) (err error) {
+	switch item.Action {
+	case actionAdd:
+		_, err = admin.CreateRoleMapping(opts.Context).RoleMapping(registryinstanceclient.RoleMapping{
+			PrincipalId: item.Principal,
+			Role:        util.GetRoleEnum(item.Role),
+		}).Execute()
+	case actionUpdate:
+		_, err = admin.UpdateRoleMapping(opts.Context, item.Principal).UpdateRole(registryinstanceclient.UpdateRole{
+			Role: util.GetRoleEnum(item.Role),
+		}).Execute()
+	case actionRevoke:
+		_, err = admin.DeleteRoleMapping(opts.Context, item.Principal).Execute()
+	}
+
+	return err
+}
+
+// resolvePrincipals verifies that every principal in the roles file exists in the organization.
+// It returns the type of each known service account, so principals which are
+// only present in the existing role mappings can be labeled in the plan.
+func resolvePrincipals(opts *options, conn api.API, desired []desiredRole) (map[string]string, error) {
+	var hasUsers, hasServiceAccounts bool
+	for _, d := range desired {
+		if d.Type == util.UserPrincipalType {
+			hasUsers = true
+		} else {
+			hasServiceAccounts = true
+		}
+	}
+
+	serviceAccounts, err := util.FetchServiceAccountPrincipals(opts.Context, conn)
+	if err != nil {
+		if hasServiceAccounts {
+			return nil, err
+		}
+		opts.Logger.Debug("Unable to list service accounts:", err)
+	}
+
+	principalTypes := make(map[string]string, len(serviceAccounts))
+	for id := range serviceAccounts {
+		principalTypes[id] = util.ServiceAccountPrincipalType
+	}
+
+	users := map[string]util.Principal{}
+	if hasUsers {
+		users, err = util.FetchUserPrincipals(opts.Context, conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var unknown []string
+	for _, d := range desired {
+		known := users
+		if d.Type == util.ServiceAccountPrincipalType {
+			known = serviceAccounts
+		}
+		if _, ok := known[d.Principal]; !ok {
+			unknown = append(unknown, d.Principal)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, opts.localizer.MustLocalizeError("registry.role.cmd.apply.error.unknownPrincipals",
+			localize.NewEntry("Principals", strings.Join(unknown, ", ")))
+	}
+
+	return principalTypes, nil
+}
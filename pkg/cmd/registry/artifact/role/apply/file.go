@@ -0,0 +1,225 @@
+package apply
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	actionAdd    = "add"
+	actionUpdate = "update"
+	actionRevoke = "revoke"
+)
+
+// roleEntry is a single principal and its desired role in the roles file
+type roleEntry struct {
+	Username       string `yaml:"username,omitempty"`
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+	Role           string `yaml:"role"`
+}
+
+// roleFile describes the YAML (or JSON) layout of the roles file
+type roleFile struct {
+	Principals []roleEntry `yaml:"principals"`
+}
+
+// desiredRole is a validated entry of the roles file
+type desiredRole struct {
+	Principal string
+	Type      string
+	Role      registryinstanceclient.RoleType
+}
+
+// planItem is a single change needed to reconcile the instance with the roles file
+type planItem struct {
+	Principal   string `json:"principal" header:"Principal"`
+	Type        string `json:"type" header:"Type"`
+	Action      string `json:"action" header:"Action"`
+	CurrentRole string `json:"currentRole,omitempty" header:"Current role"`
+	Role        string `json:"role,omitempty" header:"Role"`
+}
+
+// parseRoles reads the desired roles from a CSV or YAML document.
+// CSV documents must have a header row with the "type", "principal" and "role" columns.
+func parseRoles(fileName string, r io.Reader) ([]desiredRole, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		return parseCSV(r)
+	}
+	return parseYAML(r)
+}
+
+func parseYAML(r io.Reader) ([]desiredRole, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var f roleFile
+	if err = yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("unable to parse roles file: %w", err)
+	}
+
+	roles := make([]desiredRole, 0, len(f.Principals))
+	for i, e := range f.Principals {
+		if e.Username != "" && e.ServiceAccount != "" {
+			return nil, fmt.Errorf("entry %v: only one of username or serviceAccount can be set", i+1)
+		}
+
+		principalType := util.UserPrincipalType
+		principal := e.Username
+		if e.ServiceAccount != "" {
+			principalType = util.ServiceAccountPrincipalType
+			principal = e.ServiceAccount
+		}
+
+		role, err := newDesiredRole(principal, principalType, e.Role)
+		if err != nil {
+			return nil, fmt.Errorf("entry %v: %w", i+1, err)
+		}
+		roles = append(roles, *role)
+	}
+
+	return roles, validateUnique(roles)
+}
+
+func parseCSV(r io.Reader) ([]desiredRole, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse roles file: %w", err)
+	}
+	if len(records) == 0 {
+		return []desiredRole{}, nil
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range []string{"type", "principal", "role"} {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("roles file is missing the %q column", c)
+		}
+	}
+
+	roles := make([]desiredRole, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		role, err := newDesiredRole(rec[columns["principal"]], rec[columns["type"]], rec[columns["role"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %w", i+2, err)
+		}
+		roles = append(roles, *role)
+	}
+
+	return roles, validateUnique(roles)
+}
+
+func newDesiredRole(principal string, principalType string, role string) (*desiredRole, error) {
+	principal = strings.TrimSpace(principal)
+	if principal == "" {
+		return nil, fmt.Errorf("principal is missing")
+	}
+
+	principalType = strings.ToLower(strings.TrimSpace(principalType))
+	if !util.IsValidPrincipalType(principalType) {
+		return nil, fmt.Errorf("invalid principal type %q, valid options are: %v", principalType, util.GetAllowedPrincipalTypeEnumValuesAsString())
+	}
+
+	roleType := util.GetRoleEnum(role)
+	if roleType == "" {
+		return nil, fmt.Errorf("invalid role %q for principal %q", role, principal)
+	}
+
+	return &desiredRole{
+		Principal: principal,
+		Type:      principalType,
+		Role:      roleType,
+	}, nil
+}
+
+func validateUnique(roles []desiredRole) error {
+	seen := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		if seen[r.Principal] {
+			return fmt.Errorf("principal %q is listed more than once", r.Principal)
+		}
+		seen[r.Principal] = true
+	}
+	return nil
+}
+
+// computePlan compares the desired roles against the existing role mappings
+// and returns the additions, changes and revocations needed to reconcile them.
+// The roles of the principals which are not listed are only revoked when prune is set,
+// otherwise they are kept and returned, so that an empty or truncated file does not revoke them.
+// principalTypes is used to label principals which are only known from the existing mappings.
+func computePlan(desired []desiredRole, current []registryinstanceclient.RoleMapping, principalTypes map[string]string, prune bool) (plan []planItem, kept []string) {
+	currentRoles := make(map[string]registryinstanceclient.RoleType, len(current))
+	for _, m := range current {
+		currentRoles[m.GetPrincipalId()] = m.GetRole()
+	}
+
+	plan = []planItem{}
+	wanted := make(map[string]bool, len(desired))
+
+	for _, d := range desired {
+		wanted[d.Principal] = true
+
+		existing, ok := currentRoles[d.Principal]
+		switch {
+		case !ok:
+			plan = append(plan, planItem{
+				Principal: d.Principal,
+				Type:      d.Type,
+				Action:    actionAdd,
+				Role:      util.GetRoleLabel(d.Role),
+			})
+		case existing != d.Role:
+			plan = append(plan, planItem{
+				Principal:   d.Principal,
+				Type:        d.Type,
+				Action:      actionUpdate,
+				CurrentRole: util.GetRoleLabel(existing),
+				Role:        util.GetRoleLabel(d.Role),
+			})
+		}
+	}
+
+	revocations := []planItem{}
+	for principal, role := range currentRoles {
+		if wanted[principal] {
+			continue
+		}
+		if !prune {
+			kept = append(kept, principal)
+			continue
+		}
+		principalType, ok := principalTypes[principal]
+		if !ok {
+			principalType = util.UserPrincipalType
+		}
+		revocations = append(revocations, planItem{
+			Principal:   principal,
+			Type:        principalType,
+			Action:      actionRevoke,
+			CurrentRole: util.GetRoleLabel(role),
+		})
+	}
+	sort.Slice(revocations, func(i, j int) bool {
+		return revocations[i].Principal < revocations[j].Principal
+	})
+	sort.Strings(kept)
+
+	return append(plan, revocations...), kept
+}
@@ -0,0 +1,137 @@
+package apply
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+)
+
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		want     []desiredRole
+		wantErr  bool
+	}{
+		{
+			name:     "Should parse YAML file",
+			fileName: "roles.yaml",
+			content: `
+principals:
+  - username: alice
+    role: admin
+  - serviceAccount: srvc-acct-1
+    role: READ_ONLY
+`,
+			want: []desiredRole{
+				{Principal: "alice", Type: "user", Role: registryinstanceclient.ROLETYPE_ADMIN},
+				{Principal: "srvc-acct-1", Type: "service-account", Role: registryinstanceclient.ROLETYPE_READ_ONLY},
+			},
+		},
+		{
+			name:     "Should parse CSV file with columns in any order",
+			fileName: "roles.CSV",
+			content:  "principal,role,type\nbob,manager,user\nsrvc-acct-2,DEVELOPER,service-account\n",
+			want: []desiredRole{
+				{Principal: "bob", Type: "user", Role: registryinstanceclient.ROLETYPE_DEVELOPER},
+				{Principal: "srvc-acct-2", Type: "service-account", Role: registryinstanceclient.ROLETYPE_DEVELOPER},
+			},
+		},
+		{
+			name:     "Should throw error when CSV column is missing",
+			fileName: "roles.csv",
+			content:  "principal,role\nbob,manager\n",
+			wantErr:  true,
+		},
+		{
+			name:     "Should throw error when role is invalid",
+			fileName: "roles.yaml",
+			content:  "principals:\n  - username: alice\n    role: owner\n",
+			wantErr:  true,
+		},
+		{
+			name:     "Should throw error when principal has both username and service account",
+			fileName: "roles.yaml",
+			content:  "principals:\n  - username: alice\n    serviceAccount: srvc-acct-1\n    role: admin\n",
+			wantErr:  true,
+		},
+		{
+			name:     "Should throw error when principal is listed twice",
+			fileName: "roles.csv",
+			content:  "type,principal,role\nuser,bob,admin\nuser,bob,viewer\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRoles(tt.fileName, strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputePlan(t *testing.T) {
+	desired := []desiredRole{
+		{Principal: "alice", Type: "user", Role: registryinstanceclient.ROLETYPE_ADMIN},
+		{Principal: "bob", Type: "user", Role: registryinstanceclient.ROLETYPE_READ_ONLY},
+		{Principal: "carol", Type: "user", Role: registryinstanceclient.ROLETYPE_DEVELOPER},
+	}
+	current := []registryinstanceclient.RoleMapping{
+		{PrincipalId: "bob", Role: registryinstanceclient.ROLETYPE_ADMIN},
+		{PrincipalId: "carol", Role: registryinstanceclient.ROLETYPE_DEVELOPER},
+		{PrincipalId: "srvc-acct-1", Role: registryinstanceclient.ROLETYPE_READ_ONLY},
+	}
+
+	tests := []struct {
+		name     string
+		desired  []desiredRole
+		prune    bool
+		want     []planItem
+		wantKept []string
+	}{
+		{
+			name:    "Should revoke the roles of the principals which are not listed when pruning",
+			desired: desired,
+			prune:   true,
+			want: []planItem{
+				{Principal: "alice", Type: "user", Action: actionAdd, Role: "admin"},
+				{Principal: "bob", Type: "user", Action: actionUpdate, CurrentRole: "admin", Role: "viewer"},
+				{Principal: "srvc-acct-1", Type: "service-account", Action: actionRevoke, CurrentRole: "viewer"},
+			},
+		},
+		{
+			name:    "Should keep the roles of the principals which are not listed",
+			desired: desired,
+			want: []planItem{
+				{Principal: "alice", Type: "user", Action: actionAdd, Role: "admin"},
+				{Principal: "bob", Type: "user", Action: actionUpdate, CurrentRole: "admin", Role: "viewer"},
+			},
+			wantKept: []string{"srvc-acct-1"},
+		},
+		{
+			name:     "Should keep all the roles for an empty file",
+			want:     []planItem{},
+			wantKept: []string{"bob", "carol", "srvc-acct-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, kept := computePlan(tt.desired, current, map[string]string{"srvc-acct-1": "service-account"}, tt.prune)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("computePlan() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("computePlan() kept %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package list
 
 import (
 	"context"
+	"strings"
 
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
@@ -10,6 +11,7 @@ import (
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
 	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	connectionapi "github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
 	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
@@ -17,15 +19,24 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// row is the details of a Service Registry instance needed to print to a table
+// row is the details of a role mapping and its principal needed to print to a table
 type registryRow struct {
 	Principal string `json:"principal" header:"Principal"`
+	Name      string `json:"name,omitempty" header:"Name"`
+	Email     string `json:"email,omitempty" header:"Email"`
+	Type      string `json:"type" header:"Type"`
 	Role      string `json:"role" header:"Role"`
+
+	// mapping is the role mapping of the row, which is printed instead of the row in JSON and YAML
+	mapping registryinstanceclient.RoleMapping
 }
 
 type options struct {
-	outputFormat string
-	registryID   string
+	outputFormat  string
+	registryID    string
+	role          string
+	principalType string
+	search        string
 
 	IO             *iostreams.IOStreams
 	Connection     factory.ConnectionFunc
@@ -53,6 +64,15 @@ func NewListCommand(f *factory.Factory) *cobra.Command {
 		Example: f.Localizer.MustLocalize("registry.role.cmd.list.example"),
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.role != "" && util.GetRoleEnum(opts.role) == "" {
+				return opts.localizer.MustLocalizeError("artifact.cmd.common.error.invalidRole",
+					localize.NewEntry("AllowedRoles", util.GetAllowedRoleTypeEnumValuesAsString()))
+			}
+
+			if opts.principalType != "" && !util.IsValidPrincipalType(opts.principalType) {
+				return flagutil.InvalidValueError("type", opts.principalType, util.AllowedPrincipalTypeEnumValues...)
+			}
+
 			if opts.registryID != "" {
 				return runList(opts)
 			}
@@ -70,8 +90,13 @@ func NewListCommand(f *factory.Factory) *cobra.Command {
 
 	cmd.Flags().StringVar(&opts.registryID, "instance-id", "", opts.localizer.MustLocalize("registry.common.flag.instance.id"))
 	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "table", opts.localizer.MustLocalize("artifact.common.message.output.format"))
+	cmd.Flags().StringVar(&opts.role, "role", "", opts.localizer.MustLocalize("registry.role.cmd.list.flag.role.description"))
+	cmd.Flags().StringVar(&opts.principalType, "type", "", opts.localizer.MustLocalize("registry.role.cmd.list.flag.type.description"))
+	cmd.Flags().StringVar(&opts.search, "search", "", opts.localizer.MustLocalize("registry.role.cmd.list.flag.search.description"))
 
 	flagutil.EnableOutputFlagCompletion(cmd)
+	flagutil.EnableStaticFlagCompletion(cmd, "role", util.AllowedRoleTypeEnumValues)
+	flagutil.EnableStaticFlagCompletion(cmd, "type", util.AllowedPrincipalTypeEnumValues)
 
 	return cmd
 }
@@ -98,22 +123,63 @@ func runList(opts *options) error {
 		return registrycmdutil.TransformInstanceError(err)
 	}
 
-	if len(mappings) == 0 && format == util.TableOutputFormat {
+	principals := fetchPrincipals(opts, api)
+	rows := filterRows(opts, mapResponseItemsToRows(mappings, principals))
+
+	if len(rows) == 0 && format == util.TableOutputFormat {
 		opts.Logger.Info(opts.localizer.MustLocalize("registry.role.cmd.nomappings", localize.NewEntry("Registry", opts.registryID)))
 		return nil
 	}
 
-	return util.Dump(opts.IO.Out, format, mapResponseItemsToRows(mappings), mappings)
+	// the JSON and YAML output keeps the role mappings of the API, which scripts read
+	filtered := make([]registryinstanceclient.RoleMapping, len(rows))
+	for i, row := range rows {
+		filtered[i] = row.mapping
+	}
+
+	return util.Dump(opts.IO.Out, format, rows, filtered)
 }
 
-func mapResponseItemsToRows(artifacts []registryinstanceclient.RoleMapping) []registryRow {
+// fetchPrincipals looks up the details of the users and service accounts of the organization.
+// Details are optional, so when they cannot be fetched the raw principal IDs are listed.
+func fetchPrincipals(opts *options, conn connectionapi.API) map[string]util.Principal {
+	principals := map[string]util.Principal{}
+
+	users, err := util.FetchUserPrincipals(opts.Context, conn)
+	if err != nil {
+		opts.Logger.Debug("Unable to fetch user details:", err)
+	}
+	for id, p := range users {
+		principals[id] = p
+	}
+
+	serviceAccounts, err := util.FetchServiceAccountPrincipals(opts.Context, conn)
+	if err != nil {
+		opts.Logger.Debug("Unable to fetch service account details:", err)
+	}
+	for id, p := range serviceAccounts {
+		principals[id] = p
+	}
+
+	return principals
+}
+
+func mapResponseItemsToRows(mappings []registryinstanceclient.RoleMapping, principals map[string]util.Principal) []registryRow {
 	rows := []registryRow{}
 
-	for i := range artifacts {
-		k := (artifacts)[i]
+	for i := range mappings {
+		k := mappings[i]
 		row := registryRow{
 			Principal: k.GetPrincipalId(),
+			Type:      util.UserPrincipalType,
 			Role:      util.GetRoleLabel(k.GetRole()),
+			mapping:   k,
+		}
+
+		if p, ok := principals[k.GetPrincipalId()]; ok {
+			row.Name = p.Name
+			row.Email = p.Email
+			row.Type = p.Type
 		}
 
 		rows = append(rows, row)
@@ -121,3 +187,28 @@ func mapResponseItemsToRows(artifacts []registryinstanceclient.RoleMapping) []re
 
 	return rows
 }
+
+// filterRows applies the role, type and search filters to the rows
+func filterRows(opts *options, rows []registryRow) []registryRow {
+	role := util.GetRoleLabel(util.GetRoleEnum(opts.role))
+	search := strings.ToLower(opts.search)
+
+	filtered := []registryRow{}
+	for _, row := range rows {
+		if opts.role != "" && row.Role != role {
+			continue
+		}
+		if opts.principalType != "" && row.Type != opts.principalType {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(row.Principal), search) &&
+			!strings.Contains(strings.ToLower(row.Name), search) &&
+			!strings.Contains(strings.ToLower(row.Email), search) {
+			continue
+		}
+		filtered = append(filtered, row)
+	}
+
+	return filtered
+}
@@ -0,0 +1,79 @@
+package list
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+)
+
+func TestListRows(t *testing.T) {
+	mappings := []registryinstanceclient.RoleMapping{
+		{PrincipalId: "alice", Role: registryinstanceclient.ROLETYPE_ADMIN, PrincipalName: registryinstanceclient.PtrString("Alice")},
+		{PrincipalId: "srvc-acct-1", Role: registryinstanceclient.ROLETYPE_READ_ONLY},
+		{PrincipalId: "bob", Role: registryinstanceclient.ROLETYPE_DEVELOPER},
+	}
+	principals := map[string]util.Principal{
+		"alice":       {ID: "alice", Type: util.UserPrincipalType, Name: "Alice Smith", Email: "alice@example.com"},
+		"srvc-acct-1": {ID: "srvc-acct-1", Type: util.ServiceAccountPrincipalType, Name: "ci"},
+	}
+	rows := mapResponseItemsToRows(mappings, principals)
+
+	tests := []struct {
+		name string
+		opts options
+		want []string
+	}{
+		{name: "no filter", want: []string{"alice", "srvc-acct-1", "bob"}},
+		{name: "role", opts: options{role: "admin"}, want: []string{"alice"}},
+		{name: "type", opts: options{principalType: util.ServiceAccountPrincipalType}, want: []string{"srvc-acct-1"}},
+		{name: "search by email", opts: options{search: "EXAMPLE.com"}, want: []string{"alice"}},
+		{name: "search by name", opts: options{search: "ci"}, want: []string{"srvc-acct-1"}},
+		{name: "unknown principals are users", opts: options{principalType: util.UserPrincipalType}, want: []string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			var got []string
+			for _, row := range filterRows(&opts, rows) {
+				got = append(got, row.Principal)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("JSON keeps the role mappings of the API", func(t *testing.T) {
+		opts := options{role: "admin"}
+		filtered := filterRows(&opts, rows)
+		var out bytes.Buffer
+		if err := util.Dump(&out, util.JsonOutputFormat, filtered, []registryinstanceclient.RoleMapping{filtered[0].mapping}); err != nil {
+			t.Fatal(err)
+		}
+		var got []map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		want := []map[string]interface{}{{"principalId": "alice", "role": "ADMIN", "principalName": "Alice"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("JSON output = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("table shows the principal details", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := util.Dump(&out, util.TableOutputFormat, rows, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"Alice Smith", "alice@example.com", "ci"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("table does not contain %q:\n%v", want, out.String())
+			}
+		}
+	})
+}
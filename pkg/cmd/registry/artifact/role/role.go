@@ -2,6 +2,7 @@ package role
 
 import (
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/role/add"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/role/apply"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/role/list"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/role/revoke"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
@@ -21,6 +22,7 @@ func NewRoleCommand(f *factory.Factory) *cobra.Command {
 		add.NewAddCommand(f),
 		revoke.NewRevokeCommand(f),
 		list.NewListCommand(f),
+		apply.NewApplyCommand(f),
 	)
 
 	return cmd
@@ -16,6 +16,16 @@ const (
 	AdminRole   = "admin"
 )
 
+const (
+	UserPrincipalType           = "user"
+	ServiceAccountPrincipalType = "service-account"
+)
+
+var AllowedPrincipalTypeEnumValues = []string{
+	UserPrincipalType,
+	ServiceAccountPrincipalType,
+}
+
 var AllowedRoleTypeEnumValues = []string{
 	ViewerRole,
 	ManagerRole,
@@ -30,3 +40,18 @@ func GetAllowedArtifactStateEnumValuesAsString() string {
 func GetAllowedRoleTypeEnumValuesAsString() string {
 	return strings.Join(AllowedRoleTypeEnumValues, ", ")
 }
+
+// GetAllowedPrincipalTypeEnumValuesAsString gets types of principals as string.
+func GetAllowedPrincipalTypeEnumValuesAsString() string {
+	return strings.Join(AllowedPrincipalTypeEnumValues, ", ")
+}
+
+// IsValidPrincipalType checks if the value is one of the allowed principal types
+func IsValidPrincipalType(principalType string) bool {
+	for _, t := range AllowedPrincipalTypeEnumValues {
+		if t == principalType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apicurio/apicurio-cli/pkg/api/rbac/rbacutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	svcacctmgmtclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/serviceaccountmgmt/apiv1/client"
+)
+
+// Principal contains the details of a user or service account which can be granted a role
+type Principal struct {
+	ID    string
+	Type  string
+	Name  string
+	Email string
+}
+
+// FetchUserPrincipals returns the users of the current organization, keyed by username
+func FetchUserPrincipals(ctx context.Context, conn api.API) (map[string]Principal, error) {
+	users, err := rbacutil.FetchAllUsers(ctx, conn.RBAC().PrincipalAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	principals := make(map[string]Principal, len(users))
+	for _, u := range users {
+		principals[u.Username] = Principal{
+			ID:    u.Username,
+			Type:  UserPrincipalType,
+			Name:  strings.TrimSpace(u.FirstName + " " + u.LastName),
+			Email: u.Email,
+		}
+	}
+
+	return principals, nil
+}
+
+// serviceAccountsPageSize is the number of service accounts fetched by each request
+const serviceAccountsPageSize = 100
+
+// FetchServiceAccountPrincipals returns the service accounts visible to the current user, keyed by client ID
+func FetchServiceAccountPrincipals(ctx context.Context, conn api.API) (map[string]Principal, error) {
+	serviceAccounts, err := fetchAllServiceAccounts(func(first int32) ([]svcacctmgmtclient.ServiceAccountData, error) {
+		page, _, err := conn.ServiceAccountMgmt().GetServiceAccounts(ctx).First(first).Max(serviceAccountsPageSize).Execute()
+		return page, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	principals := make(map[string]Principal, len(serviceAccounts))
+	for _, sa := range serviceAccounts {
+		principals[sa.GetClientId()] = Principal{
+			ID:   sa.GetClientId(),
+			Type: ServiceAccountPrincipalType,
+			Name: sa.GetName(),
+		}
+	}
+
+	return principals, nil
+}
+
+// fetchAllServiceAccounts fetches the pages of service accounts until a page is not full
+func fetchAllServiceAccounts(fetchPage func(first int32) ([]svcacctmgmtclient.ServiceAccountData, error)) ([]svcacctmgmtclient.ServiceAccountData, error) {
+	var serviceAccounts []svcacctmgmtclient.ServiceAccountData
+	seen := map[string]bool{}
+	for {
+		page, err := fetchPage(int32(len(serviceAccounts)))
+		if err != nil {
+			return nil, err
+		}
+		// a server which ignores the offset returns the same page again
+		if len(page) > 0 && seen[page[0].GetClientId()] {
+			return serviceAccounts, nil
+		}
+		for _, sa := range page {
+			seen[sa.GetClientId()] = true
+		}
+
+		serviceAccounts = append(serviceAccounts, page...)
+		if len(page) < serviceAccountsPageSize {
+			return serviceAccounts, nil
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	svcacctmgmtclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/serviceaccountmgmt/apiv1/client"
+)
+
+func TestFetchAllServiceAccounts(t *testing.T) {
+	all := make([]svcacctmgmtclient.ServiceAccountData, 2*serviceAccountsPageSize+5)
+	for i := range all {
+		all[i].ClientId = svcacctmgmtclient.PtrString(fmt.Sprintf("srvc-acct-%v", i))
+	}
+
+	tests := []struct {
+		name          string
+		total         int
+		ignoresOffset bool
+		wantCount     int
+		wantRequests  int
+	}{
+		{name: "single page", total: 5, wantCount: 5, wantRequests: 1},
+		{name: "several pages", total: len(all), wantCount: len(all), wantRequests: 3},
+		{name: "full last page", total: serviceAccountsPageSize, wantCount: serviceAccountsPageSize, wantRequests: 2},
+		{name: "server ignoring the offset", total: len(all), ignoresOffset: true, wantCount: serviceAccountsPageSize, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			got, err := fetchAllServiceAccounts(func(first int32) ([]svcacctmgmtclient.ServiceAccountData, error) {
+				requests++
+				if tt.ignoresOffset {
+					first = 0
+				}
+				end := int(first) + serviceAccountsPageSize
+				if end > tt.total {
+					end = tt.total
+				}
+				return all[first:end], nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantCount || requests != tt.wantRequests {
+				t.Errorf("got %v service accounts in %v requests, want %v in %v", len(got), requests, tt.wantCount, tt.wantRequests)
+			}
+		})
+	}
+}
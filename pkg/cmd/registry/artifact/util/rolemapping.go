@@ -1,6 +1,10 @@
 package util
 
-import registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+import (
+	"strings"
+
+	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
+)
 
 func GetRoleLabel(role registryinstanceclient.RoleType) string {
 	switch role {
@@ -15,13 +19,15 @@ func GetRoleLabel(role registryinstanceclient.RoleType) string {
 	}
 }
 
+// GetRoleEnum maps a role label (admin, manager, viewer) or a role type
+// (ADMIN, DEVELOPER, READ_ONLY) to the role type used by the API
 func GetRoleEnum(role string) registryinstanceclient.RoleType {
-	switch role {
+	switch strings.ToLower(strings.TrimSpace(role)) {
 	case AdminRole:
 		return registryinstanceclient.ROLETYPE_ADMIN
-	case ManagerRole:
+	case ManagerRole, "developer":
 		return registryinstanceclient.ROLETYPE_DEVELOPER
-	case ViewerRole:
+	case ViewerRole, "read_only":
 		return registryinstanceclient.ROLETYPE_READ_ONLY
 	default:
 		return ""
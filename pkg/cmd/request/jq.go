@@ -0,0 +1,64 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/itchyny/gojq"
+)
+
+// evaluateJQ applies the jq expression of the --jq flag to the decoded JSON document and returns its results
+func evaluateJQ(expr string, data interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression %q: %w", expr, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression %q: %w", expr, err)
+	}
+
+	var results []interface{}
+	iter := code.Run(jqValue(data))
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, ok := v.(error); ok {
+			return nil, fmt.Errorf("--jq expression %q failed: %w", expr, err)
+		}
+		results = append(results, v)
+	}
+}
+
+// jqValue converts the numbers of the decoded JSON document to the types of gojq,
+// keeping integers which do not fit in an int exact
+func jqValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil && int64(int(i)) == i {
+			return int(i)
+		}
+		if i, ok := new(big.Int).SetString(value.String(), 10); ok {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case []interface{}:
+		values := make([]interface{}, len(value))
+		for i, item := range value {
+			values[i] = jqValue(item)
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			values[key] = jqValue(item)
+		}
+		return values
+	default:
+		return v
+	}
+}
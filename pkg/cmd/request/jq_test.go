@@ -0,0 +1,55 @@
+package request
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateJQ(t *testing.T) {
+	data, err := decodeJSON([]byte(`{"items":[{"id":"a","size":1},{"id":"b","size":2}],"x-key":{"v":true},"big":12345678901234567890}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: ".", want: `{"big":12345678901234567890,"items":[{"id":"a","size":1},{"id":"b","size":2}],"x-key":{"v":true}}`},
+		{expr: ".items[].id", want: `"a" "b"`},
+		{expr: ".items[-1].size", want: `2`},
+		{expr: `.["x-key"].v`, want: `true`},
+		{expr: ".items | .[0] | .id", want: `"a"`},
+		{expr: ".missing.field", want: `null`},
+		{expr: "[.items[] | select(.size > 1) | .id]", want: `["b"]`},
+		{expr: ".items | map(.size) | add", want: `3`},
+		{expr: ".big", want: `12345678901234567890`},
+		{expr: ".items.id", wantErr: true},
+		{expr: "items", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			results, err := evaluateJQ(tt.expr, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateJQ() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, r := range results {
+				b, err := json.Marshal(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, string(b))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("evaluateJQ() = %v, want %v", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
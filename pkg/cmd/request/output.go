@@ -0,0 +1,97 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"gopkg.in/yaml.v2"
+)
+
+// writeBody prints the response body in the format selected by the
+// --output, --jq and --template flags, or as it was received when none is set.
+// The expressions are only applied to successful responses, so that error
+// responses are always printed in full.
+func writeBody(out io.Writer, opts *options, body []byte, isError bool) error {
+	if len(body) == 0 {
+		return nil
+	}
+
+	switch {
+	case opts.jq != "" && !isError:
+		data, err := decodeJSON(body)
+		if err != nil {
+			return err
+		}
+		return writeJQ(out, opts.jq, data)
+	case opts.template != "" && !isError:
+		data, err := decodeJSON(body)
+		if err != nil {
+			return err
+		}
+		return writeTemplate(out, opts.template, data)
+	case opts.outputFormat == dump.JSONFormat:
+		return dump.JSON(out, body)
+	case opts.outputFormat == dump.YAMLFormat, opts.outputFormat == dump.YMLFormat:
+		var data interface{}
+		if !json.Valid(body) || yaml.Unmarshal(body, &data) != nil {
+			_, err := out.Write(body)
+			return err
+		}
+		return dump.Formatted(out, opts.outputFormat, data)
+	}
+
+	_, err := out.Write(body)
+	return err
+}
+
+// decodeJSON decodes the response body, keeping numbers as they were received
+func decodeJSON(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	return data, nil
+}
+
+// writeJQ prints every result of the --jq expression on its own line.
+// Strings are printed without quotes and other values as compact JSON.
+func writeJQ(out io.Writer, expr string, data interface{}) error {
+	results, err := evaluateJQ(expr, data)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if s, ok := result.(string); ok {
+			fmt.Fprintln(out, s)
+			continue
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(b))
+	}
+	return nil
+}
+
+// writeTemplate renders the decoded response body with the Go template of the --template flag
+func writeTemplate(out io.Writer, text string, data interface{}) error {
+	tmpl, err := template.New("response").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl.Execute(out, data)
+}
@@ -0,0 +1,180 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apicurio/apicurio-cli/pkg/api/generic"
+)
+
+// Query parameters used by the paginated list endpoints.
+// The management API uses page/size, the Service Registry instance APIs use offset/limit.
+const (
+	pageParam   = "page"
+	sizeParam   = "size"
+	offsetParam = "offset"
+	limitParam  = "limit"
+)
+
+// errNotPaginated is returned when a response is not a page of a list
+var errNotPaginated = errors.New("response is not a paginated list: expected a JSON object with an array of items")
+
+// listPage is a single page of a paginated list
+type listPage struct {
+	items []json.RawMessage
+	// total is the number of items in the whole list, or -1 when unknown
+	total int
+	// page and size are set for lists paginated with page/size
+	page int
+	size int
+	// offset and limit are set for lists paginated with offset/limit
+	offset       int
+	limit        int
+	offsetPaging bool
+}
+
+// paginate performs the request for every page of a list and returns the last response
+// with the items of all pages concatenated into a JSON array as its body.
+// Error responses are returned as they are.
+func paginate(ctx context.Context, client generic.GenericAPI, request *generic.Request) (*generic.Response, error) {
+	path, rawQuery, _ := strings.Cut(request.Path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range request.Query {
+		query[key] = append(query[key], values...)
+	}
+
+	items := []json.RawMessage{}
+	for {
+		pageRequest := *request
+		pageRequest.Path = path
+		pageRequest.Query = query
+
+		response, err := client.Do(ctx, &pageRequest)
+		if err != nil {
+			return nil, err
+		}
+		if response.IsError() {
+			return response, nil
+		}
+
+		page, err := parsePage(response.Body, query)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.items...)
+
+		if !page.hasNext() {
+			response.Body, err = json.Marshal(items)
+			return response, err
+		}
+
+		query = cloneQuery(query)
+		if page.offsetPaging {
+			query.Set(offsetParam, strconv.Itoa(page.offset+len(page.items)))
+		} else {
+			query.Set(pageParam, strconv.Itoa(page.page+1))
+		}
+	}
+}
+
+// hasNext reports whether another page has to be requested
+func (p *listPage) hasNext() bool {
+	if len(p.items) == 0 {
+		return false
+	}
+	if p.offsetPaging {
+		if p.total < 0 {
+			return p.limit > 0 && len(p.items) >= p.limit
+		}
+		return p.offset+len(p.items) < p.total
+	}
+	if len(p.items) < p.size {
+		return false
+	}
+	return p.total < 0 || p.page*p.size < p.total
+}
+
+// parsePage reads a page of a list from the response body.
+// A page/size list has an "items" array and the "page", "size" and "total" fields,
+// while an offset/limit list has a single array of items and a "count" field.
+func parsePage(body []byte, query url.Values) (*listPage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errNotPaginated
+	}
+
+	page := &listPage{total: -1}
+
+	_, hasPage := fields[pageParam]
+	_, hasSize := fields[sizeParam]
+	if rawItems, ok := fields["items"]; ok && (hasPage || hasSize) {
+		if err := json.Unmarshal(rawItems, &page.items); err != nil {
+			return nil, errNotPaginated
+		}
+		page.page = intField(fields, pageParam, intParam(query, pageParam, 1))
+		page.size = intField(fields, sizeParam, len(page.items))
+		page.total = intField(fields, "total", -1)
+		return page, nil
+	}
+
+	for _, key := range sortedKeys(fields) {
+		raw := bytes.TrimSpace(fields[key])
+		if !bytes.HasPrefix(raw, []byte("[")) {
+			continue
+		}
+		if err := json.Unmarshal(raw, &page.items); err != nil {
+			return nil, errNotPaginated
+		}
+		page.offsetPaging = true
+		page.offset = intParam(query, offsetParam, 0)
+		page.limit = intParam(query, limitParam, 0)
+		page.total = intField(fields, "count", -1)
+		return page, nil
+	}
+
+	return nil, errNotPaginated
+}
+
+// intField returns the value of a numeric field, or def when it is missing
+func intField(fields map[string]json.RawMessage, key string, def int) int {
+	var value int
+	if err := json.Unmarshal(fields[key], &value); err != nil {
+		return def
+	}
+	return value
+}
+
+// intParam returns the value of a numeric query parameter, or def when it is missing
+func intParam(query url.Values, key string, def int) int {
+	value, err := strconv.Atoi(query.Get(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func cloneQuery(query url.Values) url.Values {
+	clone := make(url.Values, len(query))
+	for key, values := range query {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
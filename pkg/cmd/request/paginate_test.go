@@ -0,0 +1,71 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/api/generic"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{`"a"`, `"b"`, `"c"`, `"d"`, `"e"`}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/pages":
+			page, _ := strconv.Atoi(query.Get("page"))
+			size, _ := strconv.Atoi(query.Get("size"))
+			start, end := (page-1)*size, page*size
+			if end > len(items) {
+				end = len(items)
+			}
+			fmt.Fprintf(w, `{"kind":"List","page":%v,"size":%v,"total":%v,"items":[%v]}`, page, size, len(items), strings.Join(items[start:end], ","))
+		case "/offsets":
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			end := offset + limit
+			if end > len(items) {
+				end = len(items)
+			}
+			fmt.Fprintf(w, `{"artifacts":[%v],"count":%v}`, strings.Join(items[offset:end], ","), len(items))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := generic.NewGenericAPIClient(&generic.Config{BaseURL: server.URL})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantBody   string
+		wantStatus int
+	}{
+		{name: "page and size", path: "/pages?page=1&size=2", wantBody: `["a","b","c","d","e"]`, wantStatus: http.StatusOK},
+		{name: "starting page", path: "/pages?page=2&size=2", wantBody: `["c","d","e"]`, wantStatus: http.StatusOK},
+		{name: "offset and limit", path: "/offsets?limit=2", wantBody: `["a","b","c","d","e"]`, wantStatus: http.StatusOK},
+		{name: "error response", path: "/missing", wantBody: `{"message":"not found"}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := paginate(context.Background(), client, &generic.Request{Method: http.MethodGet, Path: tt.path})
+			if err != nil {
+				t.Fatalf("paginate() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("paginate() status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if got := string(resp.Body); got != tt.wantBody {
+				t.Errorf("paginate() body = %v, want %v", got, tt.wantBody)
+			}
+		})
+	}
+}
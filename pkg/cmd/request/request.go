@@ -2,10 +2,19 @@ package request
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
-	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/artifact/util"
+	"github.com/apicurio/apicurio-cli/pkg/api/generic"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	coreErrors "github.com/apicurio/apicurio-cli/pkg/core/errors"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
@@ -13,6 +22,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// ValidMethods are the HTTP methods supported by the request command
+var ValidMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+}
+
+const defaultContentType = "application/json"
+
 type options struct {
 	IO         *iostreams.IOStreams
 	Logger     logging.Logger
@@ -20,8 +41,21 @@ type options struct {
 	Context    context.Context
 	Connection factory.ConnectionFunc
 
-	urlPath string
-	method  string
+	f *factory.Factory
+
+	urlPath    string
+	method     string
+	headers    []string
+	query      []string
+	data       string
+	include    bool
+	target     string
+	registryID string
+
+	outputFormat string
+	jq           string
+	template     string
+	paginate     bool
 }
 
 func NewCallCmd(f *factory.Factory) *cobra.Command {
@@ -31,6 +65,7 @@ func NewCallCmd(f *factory.Factory) *cobra.Command {
 		localizer:  f.Localizer,
 		Context:    f.Context,
 		Connection: f.Connection,
+		f:          f,
 	}
 
 	cmd := &cobra.Command{
@@ -40,43 +75,220 @@ func NewCallCmd(f *factory.Factory) *cobra.Command {
 		Example: f.Localizer.MustLocalize("request.cmd.example"),
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.method = strings.ToUpper(opts.method)
+			if !flagutil.IsValidInput(opts.method, ValidMethods...) {
+				return flagutil.InvalidValueError("method", opts.method, ValidMethods...)
+			}
+
+			if !flagutil.IsValidInput(opts.target, ValidTargets...) {
+				return flagutil.InvalidValueError("target", opts.target, ValidTargets...)
+			}
+
+			if opts.registryID != "" && !isInstanceTarget(opts.target) {
+				return opts.localizer.MustLocalizeError("request.error.instanceIdWithoutInstanceTarget")
+			}
+
+			if opts.outputFormat != "" {
+				if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			if opts.paginate && opts.method != http.MethodGet {
+				return opts.localizer.MustLocalizeError("request.error.paginateRequiresGet")
+			}
+
 			return runCmd(opts)
 		},
 	}
-	cmd.Flags().StringVar(&opts.urlPath, "path", "", "Path to send request. For example /api/kafkas_mgmt/v1/kafkas?async=true")
-	cmd.Flags().StringVar(&opts.method, "method", "GET", "HTTP method to use. (get, post)")
+	cmd.Flags().StringVar(&opts.urlPath, "path", "", f.Localizer.MustLocalize("request.cmd.flag.path.description"))
+	cmd.Flags().StringVarP(&opts.method, "method", "X", http.MethodGet, f.Localizer.MustLocalize("request.cmd.flag.method.description"))
+	cmd.Flags().StringArrayVarP(&opts.headers, "header", "H", []string{}, f.Localizer.MustLocalize("request.cmd.flag.header.description"))
+	cmd.Flags().StringArrayVar(&opts.query, "query", []string{}, f.Localizer.MustLocalize("request.cmd.flag.query.description"))
+	cmd.Flags().StringVar(&opts.data, "data", "", f.Localizer.MustLocalize("request.cmd.flag.data.description"))
+	cmd.Flags().BoolVarP(&opts.include, "include", "i", false, f.Localizer.MustLocalize("request.cmd.flag.include.description"))
+	cmd.Flags().StringVar(&opts.target, "target", TargetMgmt, flagutil.FlagDescription(f.Localizer, "request.cmd.flag.target.description", ValidTargets...))
+	cmd.Flags().StringVar(&opts.registryID, "instance-id", "", f.Localizer.MustLocalize("registry.common.flag.instance.id"))
+	cmd.Flags().StringVar(&opts.jq, "jq", "", f.Localizer.MustLocalize("request.cmd.flag.jq.description"))
+	cmd.Flags().StringVar(&opts.template, "template", "", f.Localizer.MustLocalize("request.cmd.flag.template.description"))
+	cmd.Flags().BoolVar(&opts.paginate, "paginate", false, f.Localizer.MustLocalize("request.cmd.flag.paginate.description"))
+	flagutil.NewFlagSet(cmd, f.Localizer).AddOutput(&opts.outputFormat)
+
+	_ = cmd.MarkFlagRequired("path")
+	cmd.MarkFlagsMutuallyExclusive("output", "jq", "template")
+
+	flagutil.EnableStaticFlagCompletion(cmd, "method", ValidMethods)
+	flagutil.EnableStaticFlagCompletion(cmd, "target", ValidTargets)
+
 	return cmd
 }
 
 func runCmd(opts *options) (err error) {
-	if opts.urlPath == "" {
-		return errors.New("--path is required")
+	request, err := buildRequest(opts)
+	if err != nil {
+		return err
 	}
-	opts.Logger.Info("Performing request to", opts.urlPath)
+
 	conn, err := opts.Connection()
+	if err != nil {
+		return err
+	}
 
+	baseURL, err := resolveBaseURL(opts.f, conn.API(), opts.target, opts.registryID)
 	if err != nil {
 		return err
 	}
 
-	var data interface{}
-	var response interface{}
-	if opts.method == "post" {
-		opts.Logger.Info("POST request. Reading file from standard input")
-		specifiedFile, err1 := util.CreateFileFromStdin()
-		if err1 != nil {
-			return err
-		}
-		data, response, err = conn.API().GenericAPI().POST(opts.Context, opts.urlPath, specifiedFile)
+	client := conn.API().GenericAPIWithBaseURL(baseURL)
+
+	opts.Logger.Debug("Performing", request.Method, "request to", baseURL+opts.urlPath)
+	var response *generic.Response
+	if opts.paginate {
+		response, err = paginate(opts.Context, client, request)
 	} else {
-		data, response, err = conn.API().GenericAPI().GET(opts.Context, opts.urlPath)
+		response, err = client.Do(opts.Context, request)
+	}
+	if err != nil {
+		return err
 	}
 
-	if err != nil || data == nil {
-		opts.Logger.Info("Fetching data failed", err, response)
+	if opts.include {
+		printStatusAndHeaders(opts.IO.Out, response)
+	}
+
+	if err = writeBody(opts.IO.Out, opts, response.Body, response.IsError()); err != nil {
 		return err
 	}
 
-	fmt.Fprint(opts.IO.Out, data)
-	return nil
+	return statusError(response)
+}
+
+// buildRequest creates the request from the command options
+func buildRequest(opts *options) (*generic.Request, error) {
+	header, err := parseHeaders(opts.headers)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := parseQuery(opts.query)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Get("Accept") == "" {
+		header.Set("Accept", "application/json")
+	}
+
+	request := &generic.Request{
+		Method: opts.method,
+		Path:   opts.urlPath,
+		Query:  query,
+		Header: header,
+	}
+
+	body, contentType, err := readBody(opts)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Body = body
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", contentType)
+		}
+	}
+
+	return request, nil
+}
+
+// readBody reads the request body from the --data flag.
+// The value can be the literal body, "@file" to read a file or "@-" to read standard input.
+// For backwards compatibility, standard input is read when no data is provided
+// for a method which sends a body and the input is not a terminal.
+func readBody(opts *options) (io.Reader, string, error) {
+	switch {
+	case opts.data == "@-":
+		return opts.IO.In, defaultContentType, nil
+	case strings.HasPrefix(opts.data, "@"):
+		fileName := strings.TrimPrefix(opts.data, "@")
+		// #nosec G304
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, "", err
+		}
+		contentType := mime.TypeByExtension(filepath.Ext(fileName))
+		if contentType == "" {
+			contentType = defaultContentType
+		}
+		return strings.NewReader(string(data)), contentType, nil
+	case opts.data != "":
+		return strings.NewReader(opts.data), defaultContentType, nil
+	}
+
+	switch opts.method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if !opts.IO.IsStdinTTY() {
+			opts.Logger.Info(opts.localizer.MustLocalize("request.log.info.readingStdin", localize.NewEntry("Method", opts.method)))
+			return opts.IO.In, defaultContentType, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// parseHeaders parses headers in the "Name: value" format
+func parseHeaders(headers []string) (http.Header, error) {
+	header := http.Header{}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, flagutil.InvalidValueError("header", h)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// parseQuery parses query parameters in the "key=value" format
+func parseQuery(params []string) (url.Values, error) {
+	query := url.Values{}
+	for _, p := range params {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return nil, flagutil.InvalidValueError("query", p)
+		}
+		query.Add(key, value)
+	}
+	return query, nil
+}
+
+func printStatusAndHeaders(out io.Writer, response *generic.Response) {
+	fmt.Fprintln(out, response.Proto, response.Status)
+
+	names := make([]string, 0, len(response.Header))
+	for name := range response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, v := range response.Header[name] {
+			fmt.Fprintf(out, "%v: %v\n", name, v)
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// statusError returns an error for client and server error responses.
+// The CLI exits with code 4 for client errors and 5 for server errors.
+func statusError(response *generic.Response) error {
+	if !response.IsError() {
+		return nil
+	}
+
+	code := 4
+	if response.StatusCode >= http.StatusInternalServerError {
+		code = 5
+	}
+
+	return coreErrors.NewExitError(fmt.Errorf("request failed with status %v", response.Status), code)
 }
@@ -0,0 +1,81 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+)
+
+// Targets are the servers a request can be sent to
+const (
+	TargetMgmt     = "mgmt"
+	TargetRegistry = "registry"
+	TargetCcompat  = "ccompat"
+	TargetCncf     = "cncf"
+	TargetAuth     = "auth"
+)
+
+var ValidTargets = []string{TargetMgmt, TargetRegistry, TargetCcompat, TargetCncf, TargetAuth}
+
+// isInstanceTarget reports whether the target is served by the Service Registry instance
+func isInstanceTarget(target string) bool {
+	switch target {
+	case TargetRegistry, TargetCcompat, TargetCncf:
+		return true
+	}
+	return false
+}
+
+// resolveBaseURL returns the base URL which request paths are resolved against for the target.
+// Instance targets use the Service Registry instance set by the --instance-id flag
+// or in the current context.
+func resolveBaseURL(f *factory.Factory, conn api.API, target string, registryID string) (string, error) {
+	cfg := conn.GetConfig()
+
+	switch target {
+	case TargetMgmt:
+		return cfg.ApiURL.String(), nil
+	case TargetAuth:
+		return strings.TrimSuffix(cfg.AuthURL.String(), "/"), nil
+	}
+
+	var svcConfig *servicecontext.ServiceConfig
+	if registryID != "" {
+		svcConfig = &servicecontext.ServiceConfig{ServiceRegistryID: registryID}
+	} else {
+		svcContext, err := f.ServiceContext.Load()
+		if err != nil {
+			return "", err
+		}
+
+		svcConfig, err = contextutil.GetCurrentContext(svcContext, f.Localizer)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	registry, err := contextutil.GetRegistryForServiceConfig(svcConfig, f)
+	if err != nil {
+		return "", err
+	}
+
+	registryURL := registry.GetRegistryUrl()
+	if registryURL == "" {
+		return "", f.Localizer.MustLocalizeError("request.error.missingRegistryURL", localize.NewEntry("Name", registry.GetName()))
+	}
+
+	endpoints := registrycmdutil.GetCompatibilityEndpoints(strings.TrimSuffix(registryURL, "/"))
+	switch target {
+	case TargetCcompat:
+		return endpoints.SchemaRegistry, nil
+	case TargetCncf:
+		return endpoints.CncfSchemaRegistry, nil
+	default:
+		return endpoints.CoreRegistry, nil
+	}
+}
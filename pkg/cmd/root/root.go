@@ -3,14 +3,24 @@ package root
 import (
 	"flag"
 
+	"github.com/apicurio/apicurio-cli/pkg/cmd/auth"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/cache"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/completion"
 	contextcmd "github.com/apicurio/apicurio-cli/pkg/cmd/context"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/login"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/logout"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/profile/profilecmdutil"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/registry"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/request"
 	"github.com/apicurio/apicurio-cli/pkg/cmd/serviceaccount"
+	"github.com/apicurio/apicurio-cli/pkg/cmd/whoami"
 	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/icon"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/tracing"
+	"github.com/apicurio/apicurio-cli/pkg/shared/contextutil"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -29,9 +39,49 @@ func NewRootCommand(f *factory.Factory, version string) *cobra.Command {
 		Short:         "apicurio service registry cli",
 		Long:          "",
 		Example:       "",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if flagutil.TraceEnabled() {
+				tracing.Begin(cmd.CommandPath())
+			}
+
+			// errors loading the contexts are reported by the commands which need them
+			svcContext, err := f.ServiceContext.Load()
+			if err != nil {
+				return nil
+			}
+			if svcContext.ProjectError != nil {
+				f.Logger.Info(icon.InfoPrefix(), f.Localizer.MustLocalize("root.log.info.invalidProjectContext", localize.NewEntry("Error", svcContext.ProjectError)))
+				return nil
+			}
+			if err = contextutil.CheckInstanceIDFlag(cmd, svcContext, f.Localizer); err != nil {
+				return err
+			}
+			return contextutil.ApplyProjectDefaults(cmd, svcContext.Project)
+		},
 	}
 	fs := cmd.PersistentFlags()
 	flagutil.VerboseFlag(fs)
+	flagutil.ProfileFlag(fs, f.Localizer.MustLocalize("root.cmd.flag.profile.description"))
+	_ = cmd.RegisterFlagCompletionFunc("profile", profilecmdutil.CompleteNames)
+	flagutil.RetryFlags(fs,
+		f.Localizer.MustLocalize("root.cmd.flag.retries.description"),
+		f.Localizer.MustLocalize("root.cmd.flag.retryMaxWait.description"),
+	)
+	flagutil.HTTPLogFlags(fs,
+		flagutil.FlagDescription(f.Localizer, "root.cmd.flag.httpLogLevel.description", httputil.LogLevels...),
+		f.Localizer.MustLocalize("root.cmd.flag.httpLogCurl.description"),
+	)
+	flagutil.EnableStaticFlagCompletion(cmd, "http-log-level", httputil.LogLevels)
+	flagutil.TraceFlags(fs,
+		f.Localizer.MustLocalize("root.cmd.flag.trace.description"),
+		f.Localizer.MustLocalize("root.cmd.flag.traceFile.description"),
+		f.Localizer.MustLocalize("root.cmd.flag.traceEndpoint.description", localize.NewEntry("EnvName", tracing.EndpointEnvName)),
+	)
+	flagutil.CacheFlags(fs, f.Localizer.MustLocalize("root.cmd.flag.noCache.description"))
+	flagutil.CassetteFlags(fs,
+		f.Localizer.MustLocalize("root.cmd.flag.record.description", localize.NewEntry("EnvName", httputil.RecordEnvName)),
+		f.Localizer.MustLocalize("root.cmd.flag.replay.description", localize.NewEntry("EnvName", httputil.ReplayEnvName)),
+	)
 
 	// this flag comes out of the box, but has its own basic usage text, so this overrides that
 	var help bool
@@ -41,6 +91,10 @@ func NewRootCommand(f *factory.Factory, version string) *cobra.Command {
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	cmd.AddCommand(login.NewLoginCmd(f))
 	cmd.AddCommand(logout.NewLogoutCommand(f))
+	cmd.AddCommand(auth.NewAuthCommand(f))
+	cmd.AddCommand(whoami.NewWhoAmICommand(f))
+	cmd.AddCommand(profile.NewProfileCommand(f))
+	cmd.AddCommand(cache.NewCacheCommand(f))
 	cmd.AddCommand(completion.NewCompletionCommand(f))
 
 	// Plugin command
@@ -0,0 +1,133 @@
+package whoami
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/auth/authcmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/dump"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	IO        *iostreams.IOStreams
+	localizer localize.Localizer
+	f         *factory.Factory
+
+	outputFormat string
+}
+
+// identity describes the current user and the environment they are logged in to
+type identity struct {
+	Profile    string     `json:"profile" yaml:"profile"`
+	Username   string     `json:"username,omitempty" yaml:"username,omitempty"`
+	OrgID      string     `json:"orgID,omitempty" yaml:"orgID,omitempty"`
+	OrgAdmin   bool       `json:"orgAdmin" yaml:"orgAdmin"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	APIGateway string     `json:"apiGateway,omitempty" yaml:"apiGateway,omitempty"`
+	AuthURL    string     `json:"authURL,omitempty" yaml:"authURL,omitempty"`
+}
+
+// NewWhoAmICommand creates a new command to describe the current user
+func NewWhoAmICommand(f *factory.Factory) *cobra.Command {
+	opts := &options{
+		IO:        f.IOStreams,
+		localizer: f.Localizer,
+		f:         f,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "whoami",
+		Short:   f.Localizer.MustLocalize("whoami.cmd.shortDescription"),
+		Long:    f.Localizer.MustLocalize("whoami.cmd.longDescription"),
+		Example: f.Localizer.MustLocalize("whoami.cmd.example"),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.outputFormat != "" {
+				if err := flagutil.ValidateOutput(opts.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			return runWhoAmI(opts)
+		},
+	}
+
+	flagutil.NewFlagSet(cmd, f.Localizer).AddOutput(&opts.outputFormat)
+
+	return cmd
+}
+
+func runWhoAmI(opts *options) error {
+	cfg, err := authcmdutil.LoadValidConfig(opts.f)
+	if err != nil {
+		return err
+	}
+
+	if _, err = token.Parse(cfg.AccessToken); err != nil {
+		return err
+	}
+
+	id := &identity{
+		Profile:    config.DefaultProfileName,
+		OrgAdmin:   token.IsOrgAdmin(cfg.AccessToken),
+		APIGateway: cfg.APIUrl,
+		AuthURL:    cfg.AuthURL,
+	}
+	if profile := config.ActiveProfile(cfg, flagutil.SelectedProfile()); profile != "" {
+		id.Profile = profile
+	}
+	id.Username, _ = token.GetUsername(cfg.AccessToken)
+	id.OrgID, _ = token.GetOrgID(cfg.AccessToken)
+
+	now := time.Now()
+	expires, left, err := token.GetExpiry(cfg.AccessToken, now)
+	if err != nil {
+		return err
+	}
+	if expires {
+		expiresAt := now.Add(left).Truncate(time.Second).UTC()
+		id.ExpiresAt = &expiresAt
+	}
+
+	if opts.outputFormat != "" {
+		return dump.Formatted(opts.IO.Out, opts.outputFormat, id)
+	}
+
+	printIdentity(opts, id, left)
+	return nil
+}
+
+func printIdentity(opts *options, id *identity, left time.Duration) {
+	username := id.Username
+	if username == "" {
+		username = opts.localizer.MustLocalize("whoami.log.info.tokenHasNoUsername")
+	}
+	orgID := id.OrgID
+	if orgID == "" {
+		orgID = opts.localizer.MustLocalize("whoami.log.info.tokenHasNoOrg")
+	}
+	expiry := opts.localizer.MustLocalize("whoami.log.info.tokenDoesNotExpire")
+	if id.ExpiresAt != nil {
+		expiry = opts.localizer.MustLocalize("whoami.log.info.tokenExpiry",
+			localize.NewEntry("ExpiresAt", id.ExpiresAt.Format(time.RFC3339)),
+			localize.NewEntry("Left", left.Round(time.Second)),
+		)
+	}
+
+	fmt.Fprintln(opts.IO.Out, opts.localizer.MustLocalize("whoami.log.info.identity",
+		localize.NewEntry("Profile", id.Profile),
+		localize.NewEntry("Username", username),
+		localize.NewEntry("OrgID", orgID),
+		localize.NewEntry("OrgAdmin", id.OrgAdmin),
+		localize.NewEntry("Expiry", expiry),
+		localize.NewEntry("APIGateway", id.APIGateway),
+		localize.NewEntry("AuthURL", id.AuthURL),
+	))
+}
@@ -0,0 +1,157 @@
+package whoami
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/internal/mockutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection"
+	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tkn
+}
+
+func TestRunWhoAmI(t *testing.T) {
+	localizer, err := goi18n.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	userToken := newTestToken(t, jwt.MapClaims{
+		"preferred_username": "alice",
+		"org_id":             "12345",
+		"is_org_admin":       true,
+		"exp":                expiresAt.Unix(),
+	})
+
+	tests := []struct {
+		name         string
+		accessToken  string
+		refreshToken string
+		// refreshedToken is the access token saved by the connection, which fails to refresh the tokens when it is empty
+		refreshedToken string
+		outputFormat   string
+		want           identity
+		wantText       []string
+		wantErr        bool
+	}{
+		{
+			name:         "Should describe the user of a valid token",
+			accessToken:  userToken,
+			outputFormat: "json",
+			want:         identity{Profile: config.DefaultProfileName, Username: "alice", OrgID: "12345", OrgAdmin: true, ExpiresAt: &expiresAt},
+		},
+		{
+			name: "Should describe the service account of a token",
+			accessToken: newTestToken(t, jwt.MapClaims{
+				"preferred_username": "service-account-srvc-acct-1",
+				"clientId":           "srvc-acct-1",
+				"rh-org-id":          "67890",
+				"exp":                expiresAt.Unix(),
+			}),
+			outputFormat: "json",
+			want:         identity{Profile: config.DefaultProfileName, Username: "service-account-srvc-acct-1", OrgID: "67890", ExpiresAt: &expiresAt},
+		},
+		{
+			name:         "Should describe a token without an organization or expiry",
+			accessToken:  newTestToken(t, jwt.MapClaims{"preferred_username": "alice"}),
+			outputFormat: "json",
+			want:         identity{Profile: config.DefaultProfileName, Username: "alice"},
+		},
+		{
+			name:        "Should tell when the token has no organization",
+			accessToken: newTestToken(t, jwt.MapClaims{"preferred_username": "alice"}),
+			wantText:    []string{"alice", "Token has no organization", "never"},
+		},
+		{
+			name:           "Should refresh an expired token",
+			accessToken:    newTestToken(t, jwt.MapClaims{"preferred_username": "alice", "exp": time.Now().Add(-time.Hour).Unix()}),
+			refreshToken:   "refresh",
+			refreshedToken: userToken,
+			outputFormat:   "json",
+			want:           identity{Profile: config.DefaultProfileName, Username: "alice", OrgID: "12345", OrgAdmin: true, ExpiresAt: &expiresAt},
+		},
+		{
+			name:         "Should fail when an expired token cannot be refreshed",
+			accessToken:  newTestToken(t, jwt.MapClaims{"preferred_username": "alice", "exp": time.Now().Add(-time.Hour).Unix()}),
+			refreshToken: "expired",
+			wantErr:      true,
+		},
+		{
+			name:        "Should fail with an invalid token",
+			accessToken: "not-a-token",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{AccessToken: tt.accessToken, RefreshToken: tt.refreshToken}
+			var out bytes.Buffer
+			opts := &options{
+				IO:           &iostreams.IOStreams{Out: &out, ErrOut: io.Discard},
+				localizer:    localizer,
+				outputFormat: tt.outputFormat,
+				f: &factory.Factory{
+					Config: mockutil.NewConfigMock(cfg),
+					Logger: logger,
+					Connection: func() (connection.Connection, error) {
+						if tt.refreshedToken == "" {
+							return nil, errors.New("unable to refresh the tokens")
+						}
+						cfg.AccessToken = tt.refreshedToken
+						return &connection.ConnectionMock{}, nil
+					},
+				},
+			}
+
+			err := runWhoAmI(opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWhoAmI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.outputFormat == "" {
+				for _, s := range tt.wantText {
+					if !strings.Contains(out.String(), s) {
+						t.Errorf("runWhoAmI() printed %q, want it to contain %q", out.String(), s)
+					}
+				}
+				return
+			}
+
+			var got identity
+			if err = json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("runWhoAmI() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package login
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ClientCredentialsGrant logs in with the credentials of a service account,
+// without any interaction with the user
+type ClientCredentialsGrant struct {
+	HTTPClient   *http.Client
+	Logger       logging.Logger
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// Execute runs a Client Credentials flow login
+// https://tools.ietf.org/html/rfc6749#section-4.4
+func (c *ClientCredentialsGrant) Execute(ctx context.Context, authURL *url.URL) (*oauth2.Token, error) {
+	c.Logger.Debug("Logging into", authURL, "with client credentials")
+	clientCtx, cancel := createClientContext(ctx, c.HTTPClient)
+	defer cancel()
+	provider, err := oidc.NewProvider(clientCtx, authURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &clientcredentials.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		TokenURL:     provider.Endpoint().TokenURL,
+		Scopes:       c.Scopes,
+	}
+
+	return cfg.Token(clientCtx)
+}
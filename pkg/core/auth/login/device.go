@@ -0,0 +1,242 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultPollInterval is the polling interval used when the authorization server does not set one
+	defaultPollInterval = 5 * time.Second
+	// slowDownInterval is added to the polling interval each time the authorization server asks to slow down
+	slowDownInterval = 5 * time.Second
+	// defaultCodeExpiry bounds the login when the authorization server does not set the lifetime of the codes
+	defaultCodeExpiry = 10 * time.Minute
+)
+
+// DeviceAuthorizationGrant logs in on a device which cannot open a web browser,
+// by letting the user authorize the login from the browser of another device
+type DeviceAuthorizationGrant struct {
+	HTTPClient *http.Client
+	Logger     logging.Logger
+	IO         *iostreams.IOStreams
+	Localizer  localize.Localizer
+	ClientID   string
+	Scopes     []string
+	// OnAuthorization is called before the user code is printed, such as to stop a spinner
+	OnAuthorization func()
+
+	// wait pauses between two polls of the token endpoint, it is replaced in tests
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// deviceAuthorization is the response of the device authorization endpoint
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// tokenResponse is the response of the token endpoint, which is either a token or an error
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Execute runs a Device Authorization flow login
+// https://tools.ietf.org/html/rfc8628
+func (d *DeviceAuthorizationGrant) Execute(ctx context.Context, authURL *url.URL) (*oauth2.Token, error) {
+	d.Logger.Debug("Logging into", authURL, "with the device authorization grant")
+	clientCtx, cancel := createClientContext(ctx, d.HTTPClient)
+	defer cancel()
+	provider, err := oidc.NewProvider(clientCtx, authURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err = provider.Claims(&claims); err != nil {
+		return nil, err
+	}
+	if claims.DeviceAuthorizationEndpoint == "" {
+		return nil, d.Localizer.MustLocalizeError("login.error.deviceGrantNotSupported", localize.NewEntry("URL", authURL))
+	}
+
+	authorization, err := d.authorize(ctx, claims.DeviceAuthorizationEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.OnAuthorization != nil {
+		d.OnAuthorization()
+	}
+	d.Logger.Info(d.Localizer.MustLocalize("login.log.info.deviceCode",
+		localize.NewEntry("URL", authorization.VerificationURI),
+		localize.NewEntry("UserCode", authorization.UserCode),
+	), "\n")
+	if authorization.VerificationURIComplete != "" {
+		d.Logger.Info(d.Localizer.MustLocalize("login.log.info.deviceURLComplete"), "\n")
+		fmt.Fprintln(d.IO.Out, authorization.VerificationURIComplete)
+		d.Logger.Info("")
+	}
+
+	expiry := defaultCodeExpiry
+	if authorization.ExpiresIn > 0 {
+		expiry = time.Duration(authorization.ExpiresIn) * time.Second
+	}
+	ctx, cancelPoll := context.WithTimeout(ctx, expiry)
+	defer cancelPoll()
+
+	return d.poll(ctx, provider.Endpoint().TokenURL, authorization)
+}
+
+// authorize requests a device code and a user code
+func (d *DeviceAuthorizationGrant) authorize(ctx context.Context, endpoint string) (*deviceAuthorization, error) {
+	form := url.Values{
+		"client_id": {d.ClientID},
+	}
+	if len(d.Scopes) > 0 {
+		form.Set("scope", strings.Join(d.Scopes, " "))
+	}
+
+	res, err := d.postForm(ctx, endpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization failed: %v: %s", res.Status, body)
+	}
+
+	var authorization deviceAuthorization
+	if err = json.Unmarshal(body, &authorization); err != nil {
+		return nil, fmt.Errorf("unable to parse the device authorization response: %w", err)
+	}
+	if authorization.DeviceCode == "" || authorization.UserCode == "" || authorization.VerificationURI == "" {
+		return nil, errors.New("the device authorization response is missing the device code, user code or verification URI")
+	}
+
+	return &authorization, nil
+}
+
+// poll requests the token until the user authorizes the device, backing off when the server asks to slow down
+func (d *DeviceAuthorizationGrant) poll(ctx context.Context, tokenURL string, authorization *deviceAuthorization) (*oauth2.Token, error) {
+	wait := d.wait
+	if wait == nil {
+		wait = sleep
+	}
+
+	interval := defaultPollInterval
+	if authorization.Interval > 0 {
+		interval = time.Duration(authorization.Interval) * time.Second
+	}
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {authorization.DeviceCode},
+		"client_id":   {d.ClientID},
+	}
+
+	for {
+		if err := wait(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		res, err := d.postForm(ctx, tokenURL, form)
+		if err != nil {
+			return nil, err
+		}
+		var tokenRes tokenResponse
+		err = json.NewDecoder(res.Body).Decode(&tokenRes)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the token response: %w", err)
+		}
+
+		switch tokenRes.Error {
+		case "":
+			if tokenRes.AccessToken == "" {
+				return nil, errors.New("the token response is missing the access token")
+			}
+			token := &oauth2.Token{
+				AccessToken:  tokenRes.AccessToken,
+				RefreshToken: tokenRes.RefreshToken,
+				TokenType:    tokenRes.TokenType,
+			}
+			if tokenRes.ExpiresIn > 0 {
+				token.Expiry = time.Now().Add(time.Duration(tokenRes.ExpiresIn) * time.Second)
+			}
+			return token, nil
+		case "authorization_pending":
+			d.Logger.Debug("Waiting for the device to be authorized")
+		case "slow_down":
+			interval += slowDownInterval
+			d.Logger.Debug("Slowing down polling to", interval)
+		case "access_denied":
+			return nil, d.Localizer.MustLocalizeError("login.error.deviceAccessDenied")
+		case "expired_token":
+			return nil, d.Localizer.MustLocalizeError("login.error.deviceCodeExpired")
+		default:
+			if tokenRes.ErrorDescription != "" {
+				return nil, fmt.Errorf("%v: %v", tokenRes.Error, tokenRes.ErrorDescription)
+			}
+			return nil, errors.New(tokenRes.Error)
+		}
+	}
+}
+
+func (d *DeviceAuthorizationGrant) postForm(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := d.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
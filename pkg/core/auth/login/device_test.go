@@ -0,0 +1,175 @@
+package login
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+)
+
+// newFakeAuthServer serves the discovery document, the device authorization endpoint
+// and a token endpoint which answers with the given errors before issuing a token
+func newFakeAuthServer(t *testing.T, tokenErrors []string) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	issuer := server.URL + "/auth/realms/test"
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/auth/realms/test/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                        issuer,
+			"authorization_endpoint":        issuer + "/auth",
+			"token_endpoint":                issuer + "/token",
+			"device_authorization_endpoint": issuer + "/device",
+			"jwks_uri":                      issuer + "/certs",
+		})
+	})
+	mux.HandleFunc("/auth/realms/test/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "cli" || r.FormValue("scope") != "openid" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": issuer + "/device/verify",
+			"expires_in":       600,
+		})
+	})
+	mux.HandleFunc("/auth/realms/test/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != deviceCodeGrantType || r.FormValue("device_code") != "device-code" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		if len(tokenErrors) > 0 {
+			tokenErr := tokenErrors[0]
+			tokenErrors = tokenErrors[1:]
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": tokenErr})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"token_type":    "Bearer",
+			"expires_in":    300,
+		})
+	})
+
+	return server
+}
+
+func TestDeviceAuthorizationGrant(t *testing.T) {
+	localizer, _ := goi18n.New(nil)
+	logger, _ := logging.NewStdLoggerBuilder().Streams(&bytes.Buffer{}, &bytes.Buffer{}).Build()
+
+	tests := []struct {
+		name        string
+		tokenErrors []string
+		wantWaits   []time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "Should poll until the device is authorized",
+			tokenErrors: []string{"authorization_pending", "slow_down", "authorization_pending"},
+			wantWaits:   []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second, 10 * time.Second},
+		},
+		{
+			name:        "Should fail when the user denies the login",
+			tokenErrors: []string{"authorization_pending", "access_denied"},
+			wantWaits:   []time.Duration{5 * time.Second, 5 * time.Second},
+			wantErr:     true,
+		},
+		{
+			name:        "Should fail when the device code expires",
+			tokenErrors: []string{"expired_token"},
+			wantWaits:   []time.Duration{5 * time.Second},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeAuthServer(t, tt.tokenErrors)
+			authURL, _ := url.Parse(server.URL + "/auth/realms/test")
+
+			out := &bytes.Buffer{}
+			var waits []time.Duration
+			var authorizations int
+			grant := &DeviceAuthorizationGrant{
+				HTTPClient: server.Client(),
+				Logger:     logger,
+				IO:         &iostreams.IOStreams{Out: out, ErrOut: out},
+				Localizer:  localizer,
+				ClientID:   "cli",
+				Scopes:     []string{"openid"},
+				OnAuthorization: func() {
+					authorizations++
+					if out.Len() > 0 {
+						t.Errorf("OnAuthorization() called after %q was printed", out.String())
+					}
+				},
+				wait: func(ctx context.Context, d time.Duration) error {
+					waits = append(waits, d)
+					return nil
+				},
+			}
+
+			token, err := grant.Execute(context.Background(), authURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (token.AccessToken != "access" || token.RefreshToken != "refresh") {
+				t.Errorf("Execute() token = %v, want the issued tokens", token)
+			}
+			if authorizations != 1 {
+				t.Errorf("OnAuthorization() called %v times, want once", authorizations)
+			}
+			if len(waits) != len(tt.wantWaits) {
+				t.Fatalf("Execute() waited %v, want %v", waits, tt.wantWaits)
+			}
+			for i := range waits {
+				if waits[i] != tt.wantWaits[i] {
+					t.Errorf("Execute() waited %v, want %v", waits, tt.wantWaits)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationGrantNotSupported(t *testing.T) {
+	localizer, _ := goi18n.New(nil)
+	logger, _ := logging.NewStdLoggerBuilder().Streams(&bytes.Buffer{}, &bytes.Buffer{}).Build()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer := "http://" + r.Host
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "token_endpoint": issuer + "/token"})
+	}))
+	defer server.Close()
+	authURL, _ := url.Parse(server.URL)
+
+	grant := &DeviceAuthorizationGrant{
+		HTTPClient: server.Client(),
+		Logger:     logger,
+		Localizer:  localizer,
+		ClientID:   "cli",
+	}
+	_, err := grant.Execute(context.Background(), authURL)
+	if err == nil || !strings.Contains(err.Error(), "device authorization grant") {
+		t.Errorf("Execute() error = %v, want the device grant to be unsupported", err)
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OIDC authenticates requests with access tokens of the client credentials grant.
+// The token endpoint is discovered from the OpenID Connect configuration of the issuer.
+type OIDC struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// Transport returns a transport which sets an access token of the issuer,
+// requesting a new one when it expires
+func (p *OIDC) Transport(base http.RoundTripper) http.RoundTripper {
+	return &oauth2.Transport{
+		Base: base,
+		Source: &oidcTokenSource{
+			provider: p,
+			client:   &http.Client{Transport: base},
+		},
+	}
+}
+
+// oidcTokenSource discovers the token endpoint of the issuer on the first request
+type oidcTokenSource struct {
+	provider *OIDC
+	client   *http.Client
+
+	once   sync.Once
+	source oauth2.TokenSource
+	err    error
+}
+
+func (s *oidcTokenSource) Token() (*oauth2.Token, error) {
+	s.once.Do(func() {
+		// both the discovery and the token requests are sent with the base transport
+		ctx := oidc.ClientContext(context.Background(), s.client)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+
+		issuer, err := oidc.NewProvider(ctx, s.provider.IssuerURL)
+		if err != nil {
+			s.err = err
+			return
+		}
+
+		cfg := &clientcredentials.Config{
+			ClientID:     s.provider.ClientID,
+			ClientSecret: s.provider.ClientSecret,
+			TokenURL:     issuer.Endpoint().TokenURL,
+			Scopes:       s.provider.Scopes,
+		}
+		s.source = cfg.TokenSource(ctx)
+	})
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.source.Token()
+}
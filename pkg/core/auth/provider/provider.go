@@ -0,0 +1,177 @@
+// Package provider authenticates the requests sent to the APIs with the authentication provider of a context.
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"golang.org/x/oauth2"
+)
+
+// Names of the authentication providers
+const (
+	// TypeNone sends requests without authentication
+	TypeNone = "none"
+	// TypeBasic authenticates requests with HTTP basic authentication
+	TypeBasic = "basic"
+	// TypeBearer authenticates requests with a static bearer token
+	TypeBearer = "bearer"
+	// TypeOIDC authenticates requests with tokens of the client credentials grant of any OpenID Connect issuer
+	TypeOIDC = "oidc"
+)
+
+// Types are the names of the authentication providers
+var Types = []string{TypeNone, TypeBasic, TypeBearer, TypeOIDC}
+
+// Environment variables of the secrets of the providers, used when their file is not set
+const (
+	PasswordEnvName     = "APICR_AUTH_PASSWORD"
+	TokenEnvName        = "APICR_AUTH_TOKEN"
+	ClientSecretEnvName = "APICR_AUTH_CLIENT_SECRET"
+)
+
+// Provider authenticates the requests sent to the APIs
+type Provider interface {
+	// Transport returns a transport which authenticates the requests sent with base
+	Transport(base http.RoundTripper) http.RoundTripper
+}
+
+// New creates the authentication provider selected by cfg.
+// The secrets of the provider are read from their files, or from the environment.
+func New(cfg *servicecontext.AuthConfig) (Provider, error) {
+	switch cfg.Type {
+	case TypeNone:
+		return &None{}, nil
+	case TypeBasic:
+		password, err := readSecret(cfg.PasswordFile, PasswordEnvName)
+		if err != nil {
+			return nil, err
+		}
+		return &Basic{Username: cfg.Username, Password: password}, nil
+	case TypeBearer:
+		token, err := readSecret(cfg.TokenFile, TokenEnvName)
+		if err != nil {
+			return nil, err
+		}
+		return &Bearer{Token: token}, nil
+	case TypeOIDC:
+		clientSecret, err := readSecret(cfg.ClientSecretFile, ClientSecretEnvName)
+		if err != nil {
+			return nil, err
+		}
+		return &OIDC{
+			IssuerURL:    cfg.IssuerURL,
+			ClientID:     cfg.ClientID,
+			ClientSecret: clientSecret,
+			Scopes:       cfg.Scopes,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown authentication provider %q, valid providers are: %v", cfg.Type, strings.Join(Types, ", "))
+}
+
+// Principal returns who the provider authenticates the requests as.
+// Static bearer tokens are identified by their subject, or by their hash when they are not JWTs.
+func Principal(p Provider) string {
+	switch p := p.(type) {
+	case *Basic:
+		return TypeBasic + ":" + p.Username
+	case *Bearer:
+		if subject, ok := token.GetSubject(p.Token); ok {
+			return TypeBearer + ":" + subject
+		}
+		sum := sha256.Sum256([]byte(p.Token))
+		return TypeBearer + ":" + hex.EncodeToString(sum[:])
+	case *OIDC:
+		return TypeOIDC + ":" + p.IssuerURL + " " + p.ClientID
+	default:
+		return TypeNone
+	}
+}
+
+// IsValidType reports whether name is the name of an authentication provider
+func IsValidType(name string) bool {
+	for _, t := range Types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readSecret reads a secret from file, or from the environment variable when file is not set
+func readSecret(file string, envName string) (string, error) {
+	if file == "" {
+		secret := os.Getenv(envName)
+		if secret == "" {
+			return "", fmt.Errorf("the secret of the authentication provider is not set, set its file in the context or the %v environment variable", envName)
+		}
+		return secret, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the secret of the authentication provider: %w", err)
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("the secret file %v of the authentication provider is empty", file)
+	}
+	return secret, nil
+}
+
+// None sends requests without authentication
+type None struct{}
+
+// Transport returns base
+func (p *None) Transport(base http.RoundTripper) http.RoundTripper {
+	return base
+}
+
+// Basic authenticates requests with HTTP basic authentication
+type Basic struct {
+	Username string
+	Password string
+}
+
+// Transport returns a transport which sets the basic authentication header
+func (p *Basic) Transport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// the request of the caller must not be modified
+		req = req.Clone(req.Context())
+		req.SetBasicAuth(p.Username, p.Password)
+		return base.RoundTrip(req)
+	})
+}
+
+// Bearer authenticates requests with a static bearer token
+type Bearer struct {
+	Token string
+}
+
+// Transport returns a transport which sets the bearer token
+func (p *Bearer) Transport(base http.RoundTripper) http.RoundTripper {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{
+			AccessToken: p.Token,
+		},
+	)
+
+	return &oauth2.Transport{
+		Base:   base,
+		Source: oauth2.ReuseTokenSource(nil, ts),
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
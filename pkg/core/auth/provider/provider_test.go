@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+)
+
+func TestNew(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(TokenEnvName, "from-env")
+	t.Setenv(PasswordEnvName, "")
+
+	tests := []struct {
+		name    string
+		cfg     servicecontext.AuthConfig
+		want    Provider
+		wantErr bool
+	}{
+		{name: "none", cfg: servicecontext.AuthConfig{Type: TypeNone}, want: &None{}},
+		{name: "basic from file", cfg: servicecontext.AuthConfig{Type: TypeBasic, Username: "user", PasswordFile: secretFile}, want: &Basic{Username: "user", Password: "from-file"}},
+		{name: "basic without password", cfg: servicecontext.AuthConfig{Type: TypeBasic, Username: "user"}, wantErr: true},
+		{name: "bearer from env", cfg: servicecontext.AuthConfig{Type: TypeBearer}, want: &Bearer{Token: "from-env"}},
+		{name: "oidc", cfg: servicecontext.AuthConfig{Type: TypeOIDC, IssuerURL: "https://issuer", ClientID: "client", ClientSecretFile: secretFile}, want: &OIDC{IssuerURL: "https://issuer", ClientID: "client", ClientSecret: "from-file"}},
+		{name: "unknown", cfg: servicecontext.AuthConfig{Type: "kerberos"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("New() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestOIDCTransport(t *testing.T) {
+	var tokenRequests int32
+	var gotAuth string
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"token_endpoint":         srv.URL + "/token",
+			"authorization_endpoint": srv.URL + "/auth",
+			"jwks_uri":               srv.URL + "/certs",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		if r.FormValue("grant_type") != "client_credentials" {
+			http.Error(w, "unsupported grant", http.StatusBadRequest)
+			return
+		}
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "secret" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "issued-token",
+			"token_type":   "Bearer",
+			"expires_in":   300,
+		})
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	})
+
+	p := &OIDC{IssuerURL: srv.URL, ClientID: "client", ClientSecret: "secret"}
+	client := &http.Client{Transport: p.Transport(http.DefaultTransport)}
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Get(srv.URL + "/api")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	if gotAuth != "Bearer issued-token" {
+		t.Errorf("Authorization header = %q, want the issued token", gotAuth)
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("token requests = %v, want the token to be reused", n)
+	}
+}
+
+func TestPrincipal(t *testing.T) {
+	tests := []struct {
+		provider Provider
+		want     string
+	}{
+		{provider: &None{}, want: "none"},
+		{provider: &Basic{Username: "alice", Password: "secret"}, want: "basic:alice"},
+		{provider: &OIDC{IssuerURL: "https://sso.example.com", ClientID: "svc", ClientSecret: "secret"}, want: "oidc:https://sso.example.com svc"},
+		{provider: &Bearer{Token: "opaque"}, want: "bearer:6d229884c1268bb0ab32d8da315d0fe52f9147228bd830a37bc9fb28a954940d"},
+	}
+	for _, tt := range tests {
+		if got := Principal(tt.provider); got != tt.want {
+			t.Errorf("Principal(%T) = %v, want %v", tt.provider, got, tt.want)
+		}
+	}
+}
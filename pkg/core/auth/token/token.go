@@ -144,6 +144,39 @@ func GetUsername(tokenStr string) (username string, ok bool) {
 	return username, ok
 }
 
+// GetSubject extracts the subject claim value from the JWT
+func GetSubject(tokenStr string) (subject string, ok bool) {
+	if tokenStr == "" {
+		return "", false
+	}
+	accessTkn, err := Parse(tokenStr)
+	if err != nil {
+		return "", false
+	}
+	tknClaims, _ := MapClaims(accessTkn)
+	sub, ok := tknClaims["sub"]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%v", sub), true
+}
+
+// GetOrgID extracts the organization identifier claim value from the JWT
+func GetOrgID(tokenStr string) (orgID string, ok bool) {
+	accessTkn, err := Parse(tokenStr)
+	if err != nil {
+		return "", false
+	}
+	tknClaims, _ := MapClaims(accessTkn)
+	for _, claim := range []string{"org_id", "rh-org-id"} {
+		if o, ok := tknClaims[claim]; ok {
+			return fmt.Sprintf("%v", o), true
+		}
+	}
+
+	return "", false
+}
+
 // IsOrgAdmin returns the value of the `is_org_admin` claim
 func IsOrgAdmin(tokenStr string) bool {
 	accessTkn, _ := Parse(tokenStr)
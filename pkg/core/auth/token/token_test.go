@@ -0,0 +1,103 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tkn
+}
+
+func TestGetOrgID(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		wantOrgID string
+		wantOk    bool
+	}{
+		{
+			name:      "Should read the org_id claim of user tokens",
+			token:     newTestToken(t, jwt.MapClaims{"preferred_username": "alice", "org_id": "12345"}),
+			wantOrgID: "12345",
+			wantOk:    true,
+		},
+		{
+			name:      "Should read the rh-org-id claim of service account tokens",
+			token:     newTestToken(t, jwt.MapClaims{"clientId": "srvc-acct-1", "rh-org-id": "67890"}),
+			wantOrgID: "67890",
+			wantOk:    true,
+		},
+		{
+			name:      "Should print numeric org IDs without an exponent",
+			token:     newTestToken(t, jwt.MapClaims{"org_id": 12345}),
+			wantOrgID: "12345",
+			wantOk:    true,
+		},
+		{
+			name:  "Should not find an org ID in tokens without the claim",
+			token: newTestToken(t, jwt.MapClaims{"preferred_username": "alice"}),
+		},
+		{
+			name:  "Should not find an org ID in an invalid token",
+			token: "not-a-token",
+		},
+		{
+			name: "Should not find an org ID without a token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgID, ok := GetOrgID(tt.token)
+			if orgID != tt.wantOrgID || ok != tt.wantOk {
+				t.Errorf("GetOrgID() = %q, %v, want %q, %v", orgID, ok, tt.wantOrgID, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGetExpiry(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		token       string
+		wantExpires bool
+		wantLeft    time.Duration
+	}{
+		{
+			name:        "Should return the time left before a valid token expires",
+			token:       newTestToken(t, jwt.MapClaims{"exp": now.Add(time.Hour).Unix()}),
+			wantExpires: true,
+			wantLeft:    time.Hour,
+		},
+		{
+			name:        "Should return a negative time left for an expired token",
+			token:       newTestToken(t, jwt.MapClaims{"exp": now.Add(-time.Hour).Unix()}),
+			wantExpires: true,
+			wantLeft:    -time.Hour,
+		},
+		{
+			name:  "Should not expire tokens without an expiry",
+			token: newTestToken(t, jwt.MapClaims{"sub": "alice"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expires, left, err := GetExpiry(tt.token, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the expiry of the tokens is in seconds
+			if diff := left - tt.wantLeft; expires != tt.wantExpires || diff <= -time.Second || diff >= time.Second {
+				t.Errorf("GetExpiry() = %v, %v, want %v, %v", expires, left, tt.wantExpires, tt.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package cache stores HTTP responses on disk, with a directory for each instance of the services.
+// Immutable content never expires, while the other entries expire after their time to live.
+// When the cache grows over its size limit, the least recently used entries are evicted.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// DirName is the name of the cache directory, next to the config file
+	DirName = "cache"
+
+	// DefaultTTL is how long the entries which can change are cached
+	DefaultTTL = 5 * time.Minute
+	// DefaultMaxSize is the size the cache can grow to, in bytes
+	DefaultMaxSize int64 = 100 * humanize.MByte
+
+	entrySuffix = ".json"
+)
+
+// Entry is a cached response
+type Entry struct {
+	Instance string      `json:"instance"`
+	Key      string      `json:"key"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Body     []byte      `json:"body,omitempty"`
+	Created  time.Time   `json:"created"`
+	// Expires is the zero time for immutable content, which never expires
+	Expires time.Time `json:"expires"`
+}
+
+// Expired tells if the entry expired at the given time
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.Expires.IsZero() && now.After(e.Expires)
+}
+
+// Response returns the cached response to the request
+func (e *Entry) Response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+	}
+}
+
+// Stats describes the entries cached for an instance
+type Stats struct {
+	Instance string
+	Entries  int
+	Expired  int
+	Size     int64
+}
+
+// Cache is a cache of responses in a directory
+type Cache struct {
+	Dir string
+	// MaxSize is the size the cache can grow to, in bytes. The size is not limited when it is not positive.
+	MaxSize int64
+}
+
+// New creates a cache in the directory, limited to the given size
+func New(dir string, maxSize int64) *Cache {
+	return &Cache{Dir: dir, MaxSize: maxSize}
+}
+
+// DefaultDir returns the cache directory, next to the config file
+func DefaultDir() (string, error) {
+	cfgFile, err := config.NewFile().Location()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cfgFile), DirName), nil
+}
+
+// Open returns the cache in the default directory, limited to the size set in the config
+func Open(cfg *config.Config) (*Cache, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := DefaultMaxSize
+	if cfg.CacheMaxSize != "" {
+		size, err := humanize.ParseBytes(cfg.CacheMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache_max_size \"%v\" in the config file: %w", cfg.CacheMaxSize, err)
+		}
+		maxSize = int64(size)
+	}
+
+	return New(dir, maxSize), nil
+}
+
+// Get returns the entry of the key cached for the instance, or nil when there is no entry or it expired
+func (c *Cache) Get(instance string, key string) (*Entry, error) {
+	path := c.entryPath(instance, key)
+	entry, err := readEntry(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// entries of other keys stored at the same path are replaced when they are put
+	if entry.Instance != instance || entry.Key != key {
+		return nil, nil
+	}
+	if entry.Expired(now) {
+		_ = os.Remove(path)
+		return nil, nil
+	}
+
+	// the modification time of the entries is the time they were last used, to evict the least recently used entries
+	_ = os.Chtimes(path, now, now)
+	return entry, nil
+}
+
+// Put caches the entry, and evicts the least recently used entries when the cache grows over its size limit.
+// Entries larger than the size limit are not cached.
+func (c *Cache) Put(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if c.MaxSize > 0 && int64(len(data)) > c.MaxSize {
+		return nil
+	}
+
+	path := c.entryPath(entry.Instance, entry.Key)
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// the entry is replaced atomically, as other commands can read it at the same time
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return c.evict()
+}
+
+// Clear removes the entries cached for the instance, or all the entries when the instance is empty
+func (c *Cache) Clear(instance string) error {
+	if instance == "" {
+		return os.RemoveAll(c.Dir)
+	}
+	return os.RemoveAll(filepath.Join(c.Dir, instanceDir(instance)))
+}
+
+// Stats returns the statistics of the instances which have cached entries, sorted by instance
+func (c *Cache) Stats() ([]Stats, error) {
+	files, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	byInstance := map[string]*Stats{}
+	for _, file := range files {
+		entry, err := readEntry(file.path)
+		if err != nil {
+			continue
+		}
+		stats, ok := byInstance[entry.Instance]
+		if !ok {
+			stats = &Stats{Instance: entry.Instance}
+			byInstance[entry.Instance] = stats
+		}
+		stats.Entries++
+		stats.Size += file.size
+		if entry.Expired(now) {
+			stats.Expired++
+		}
+	}
+
+	all := make([]Stats, 0, len(byInstance))
+	for _, stats := range byInstance {
+		all = append(all, *stats)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Instance < all[j].Instance })
+	return all, nil
+}
+
+// evict removes the least recently used entries until the cache fits in its size limit
+func (c *Cache) evict() error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	files, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	if size <= c.MaxSize {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].used.Before(files[j].used) })
+	for _, file := range files {
+		if size <= c.MaxSize {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		size -= file.size
+	}
+	return nil
+}
+
+// entryFile is the file of a cached entry
+type entryFile struct {
+	path string
+	size int64
+	used time.Time
+}
+
+// entries returns the files of all the cached entries
+func (c *Cache) entries() ([]entryFile, error) {
+	var files []entryFile
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, entrySuffix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// the entry was removed by another command
+			return nil
+		}
+		files = append(files, entryFile{path: path, size: info.Size(), used: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+func (c *Cache) entryPath(instance string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, instanceDir(instance), hex.EncodeToString(sum[:])+entrySuffix)
+}
+
+func readEntry(path string) (*Entry, error) {
+	// #nosec G304
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// instanceDir returns the name of the directory of the instance, with only the characters which are valid in file names
+func instanceDir(instance string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, instance)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	c := New(t.TempDir(), 0)
+
+	put := func(instance string, key string, body string, ttl time.Duration) {
+		entry := &Entry{Instance: instance, Key: key, Status: 200, Body: []byte(body), Created: time.Now()}
+		if ttl != 0 {
+			entry.Expires = entry.Created.Add(ttl)
+		}
+		if err := c.Put(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(instance string, key string) *Entry {
+		entry, err := c.Get(instance, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry
+	}
+
+	put("registry:8080/apis/registry/v2", "/ids/globalIds/1", "content", 0)
+	put("registry:8080/apis/registry/v2", "/admin/artifactTypes", "types", -time.Second)
+	put("abc", "/registries/abc", "instance", time.Hour)
+
+	if entry := get("registry:8080/apis/registry/v2", "/ids/globalIds/1"); entry == nil || !bytes.Equal(entry.Body, []byte("content")) {
+		t.Errorf("immutable content = %+v, want it cached", entry)
+	} else if status := entry.Response(nil).Status; status != "200 OK" {
+		t.Errorf("cached response status = %q, want \"200 OK\"", status)
+	}
+	if entry := get("other:8080/apis/registry/v2", "/ids/globalIds/1"); entry != nil {
+		t.Errorf("content of another instance = %+v, want nothing", entry)
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Instance != "abc" || stats[1].Entries != 2 || stats[1].Expired != 1 {
+		t.Errorf("stats = %+v, want 2 instances with an expired entry", stats)
+	}
+
+	if entry := get("registry:8080/apis/registry/v2", "/admin/artifactTypes"); entry != nil {
+		t.Errorf("expired entry = %+v, want nothing", entry)
+	}
+
+	if err = c.Clear("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if entry := get("abc", "/registries/abc"); entry != nil {
+		t.Errorf("entry of cleared instance = %+v, want nothing", entry)
+	}
+	if err = c.Clear(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(c.Dir); !os.IsNotExist(err) {
+		t.Errorf("cache directory still exists after clearing it: %v", err)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := New(t.TempDir(), 0)
+	body := bytes.Repeat([]byte("a"), 1000)
+
+	for _, key := range []string{"1", "2", "3"} {
+		if err := c.Put(&Entry{Instance: "registry", Key: key, Status: 200, Body: body}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first entry is used after the second one
+	past := time.Now().Add(-time.Hour)
+	for i, key := range []string{"2", "1", "3"} {
+		used := past.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(c.entryPath("registry", key), used, used); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(c.Dir, "registry", "*"+entrySuffix))
+	if err != nil || len(files) != 3 {
+		t.Fatalf("got %v entries (%v), want 3", len(files), err)
+	}
+	info, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the limit leaves room for the new entry and one of the others
+	c.MaxSize = 2 * info.Size()
+	if err = c.Put(&Entry{Instance: "registry", Key: "4", Status: 200, Body: body}); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{"1": false, "2": false, "3": true, "4": true} {
+		entry, err := c.Get("registry", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (entry != nil) != want {
+			t.Errorf("entry %v cached = %v, want %v", key, entry != nil, want)
+		}
+	}
+}
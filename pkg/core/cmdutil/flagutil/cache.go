@@ -0,0 +1,24 @@
+// This file contains functions used to implement the '--no-cache' command line option.
+
+package flagutil
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// CacheFlags adds the flag which disables the cache of the responses to the given set of command line flags.
+func CacheFlags(flags *pflag.FlagSet, noCacheDescription string) {
+	flags.BoolVar(
+		&noCache,
+		"no-cache",
+		false,
+		noCacheDescription,
+	)
+}
+
+// CacheDisabled returns a boolean flag that indicates if the requests are sent without using the cache
+func CacheDisabled() bool {
+	return noCache
+}
+
+var noCache bool
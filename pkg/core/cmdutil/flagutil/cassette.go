@@ -0,0 +1,49 @@
+// This file contains functions used to implement the '--record' and '--replay' command line options.
+
+package flagutil
+
+import (
+	"os"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+	"github.com/spf13/pflag"
+)
+
+// CassetteFlags adds the flags which record and replay the HTTP interactions to the given set of command line flags.
+func CassetteFlags(flags *pflag.FlagSet, recordDescription string, replayDescription string) {
+	flags.StringVar(
+		&record,
+		"record",
+		"",
+		recordDescription,
+	)
+	flags.StringVar(
+		&replay,
+		"replay",
+		"",
+		replayDescription,
+	)
+}
+
+// RecordCassette returns the cassette the HTTP interactions are recorded to, set with the record flag
+// or with the APICR_RECORD environment variable
+func RecordCassette() string {
+	if record != "" {
+		return record
+	}
+	return os.Getenv(httputil.RecordEnvName)
+}
+
+// ReplayCassette returns the cassette the HTTP interactions are replayed from, set with the replay flag
+// or with the APICR_REPLAY environment variable
+func ReplayCassette() string {
+	if replay != "" {
+		return replay
+	}
+	return os.Getenv(httputil.ReplayEnvName)
+}
+
+var (
+	record string
+	replay string
+)
@@ -0,0 +1,51 @@
+// This file contains functions used to implement the '--http-log-level' and '--http-log-curl' command line options.
+
+package flagutil
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+	"github.com/spf13/pflag"
+)
+
+// HTTPLogFlags adds the flags which configure the logging of the HTTP requests in debug mode
+// to the given set of command line flags.
+func HTTPLogFlags(flags *pflag.FlagSet, levelDescription string, curlDescription string) {
+	flags.StringVar(
+		&httpLogLevel,
+		"http-log-level",
+		httputil.LogLevelErrors,
+		levelDescription,
+	)
+	flags.BoolVar(
+		&httpLogCurl,
+		"http-log-curl",
+		false,
+		curlDescription,
+	)
+	httpLogFlags = flags
+}
+
+// HTTPLogLevel returns the level set with the http-log-level flag, and whether the flag was set
+func HTTPLogLevel() (string, bool) {
+	return httpLogLevel, httpLogFlagChanged("http-log-level")
+}
+
+// HTTPLogCurl returns whether the http-log-curl flag renders the requests as curl commands, and whether the flag was set
+func HTTPLogCurl() (bool, bool) {
+	return httpLogCurl, httpLogFlagChanged("http-log-curl")
+}
+
+func httpLogFlagChanged(name string) bool {
+	if httpLogFlags == nil {
+		return false
+	}
+	flag := httpLogFlags.Lookup(name)
+	return flag != nil && flag.Changed
+}
+
+var (
+	httpLogLevel string
+	httpLogCurl  bool
+	// httpLogFlags is the set of the HTTP log flags, which tells whether they were set
+	httpLogFlags *pflag.FlagSet
+)
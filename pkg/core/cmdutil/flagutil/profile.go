@@ -0,0 +1,30 @@
+// This file contains functions used to implement the '--profile' command line option.
+
+package flagutil
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/spf13/pflag"
+)
+
+// ProfileFlag adds the login profile flag to the given set of command line flags.
+func ProfileFlag(flags *pflag.FlagSet, description string) {
+	flags.StringVar(
+		&profile,
+		"profile",
+		"",
+		description,
+	)
+}
+
+// SelectedProfile returns the login profile selected with the profile flag,
+// or with the APICR_PROFILE environment variable
+func SelectedProfile() string {
+	if profile != "" {
+		return profile
+	}
+	return config.SelectedProfile()
+}
+
+// profile is the name of the login profile selected with the profile flag
+var profile string
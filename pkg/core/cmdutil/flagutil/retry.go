@@ -0,0 +1,52 @@
+// This file contains functions used to implement the '--retries' and '--retry-max-wait' command line options.
+
+package flagutil
+
+import (
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+	"github.com/spf13/pflag"
+)
+
+// RetryFlags adds the flags which configure the retries of the requests to the given set of command line flags.
+func RetryFlags(flags *pflag.FlagSet, retriesDescription string, maxWaitDescription string) {
+	flags.IntVar(
+		&retries,
+		"retries",
+		httputil.DefaultRetries,
+		retriesDescription,
+	)
+	flags.DurationVar(
+		&retryMaxWait,
+		"retry-max-wait",
+		httputil.DefaultRetryMaxWait,
+		maxWaitDescription,
+	)
+	retryFlags = flags
+}
+
+// Retries returns the number of retries set with the retries flag, and whether the flag was set
+func Retries() (int, bool) {
+	return retries, retryFlagChanged("retries")
+}
+
+// RetryMaxWait returns the longest wait before a retry set with the retry-max-wait flag, and whether the flag was set
+func RetryMaxWait() (time.Duration, bool) {
+	return retryMaxWait, retryFlagChanged("retry-max-wait")
+}
+
+func retryFlagChanged(name string) bool {
+	if retryFlags == nil {
+		return false
+	}
+	flag := retryFlags.Lookup(name)
+	return flag != nil && flag.Changed
+}
+
+var (
+	retries      int
+	retryMaxWait time.Duration
+	// retryFlags is the set of the retry flags, which tells whether they were set
+	retryFlags *pflag.FlagSet
+)
@@ -0,0 +1,56 @@
+// This file contains functions used to implement the '--trace', '--trace-file' and '--trace-endpoint' command line options.
+
+package flagutil
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/tracing"
+	"github.com/spf13/pflag"
+)
+
+// TraceFlags adds the flags which trace the command and its requests to the given set of command line flags.
+func TraceFlags(flags *pflag.FlagSet, traceDescription string, fileDescription string, endpointDescription string) {
+	flags.BoolVar(
+		&trace,
+		"trace",
+		false,
+		traceDescription,
+	)
+	flags.StringVar(
+		&traceFile,
+		"trace-file",
+		"",
+		fileDescription,
+	)
+	flags.StringVar(
+		&traceEndpoint,
+		"trace-endpoint",
+		"",
+		endpointDescription,
+	)
+}
+
+// TraceEnabled returns a boolean flag that indicates if the command is traced.
+// Exporting the trace enables it.
+func TraceEnabled() bool {
+	return trace || traceFile != "" || traceEndpoint != ""
+}
+
+// TraceFile returns the file the trace is exported to, set with the trace-file flag
+func TraceFile() string {
+	return traceFile
+}
+
+// TraceEndpoint returns the OTLP/HTTP endpoint the trace is exported to, set with the trace-endpoint flag
+// or with the environment variables of OpenTelemetry
+func TraceEndpoint() string {
+	if traceEndpoint != "" {
+		return traceEndpoint
+	}
+	return tracing.EndpointFromEnvironment()
+}
+
+var (
+	trace         bool
+	traceFile     string
+	traceEndpoint string
+)
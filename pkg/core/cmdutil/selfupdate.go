@@ -5,6 +5,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/apicurio/apicurio-cli/internal/build"
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 	"github.com/blang/semver"
@@ -81,8 +82,10 @@ func DoSelfUpdateOnceADay(f *factory.Factory) (bool, error) {
 		if err != nil {
 			return false, err
 		}
-		cfg.LastUpdated = time.Now().UnixMilli()
-		err = f.Config.Save(cfg)
+		err = f.Config.Update(func(cfg *config.Config) error {
+			cfg.LastUpdated = time.Now().UnixMilli()
+			return nil
+		})
 		if err != nil {
 			return false, err
 		}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/credentials"
+)
+
+// Keys of the credentials of the config in the credential store
+const (
+	defaultCredentialsKey        = "default"
+	tokensCredentialsKeyPrefix   = "tokens/"
+	profilesCredentialsKeyPrefix = "profiles/"
+)
+
+// targetStoreName returns the name of the credential store selected by the environment or by the config,
+// which the credentials are saved to
+func targetStoreName(cfg *Config) string {
+	if name := os.Getenv(credentials.StoreEnvName); name != "" {
+		return name
+	}
+	return cfg.CredentialStore
+}
+
+// sourceStoreName returns the name of the credential store the credentials of the config were saved to.
+// The store is recorded in the config, and older configs only record it when it was not selected by the environment.
+func sourceStoreName(cfg *Config) string {
+	if !isPlaintextStore(cfg.CredentialStore) {
+		return cfg.CredentialStore
+	}
+	return targetStoreName(cfg)
+}
+
+func isPlaintextStore(name string) bool {
+	return name == "" || name == credentials.PlaintextStore
+}
+
+func sameStore(a string, b string) bool {
+	return a == b || (isPlaintextStore(a) && isPlaintextStore(b))
+}
+
+// loadCredentials reads the tokens and client secrets of the config from its credential store, in a single operation.
+// Credentials which are still in the config file are kept until the config is saved to the store.
+func loadCredentials(cfg *Config, file string) error {
+	name := sourceStoreName(cfg)
+	store, err := credentials.NewStore(name, filepath.Dir(file))
+	if err != nil || store == nil {
+		return err
+	}
+
+	stored, err := store.Get(credentialKeys(cfg))
+	if err != nil {
+		return err
+	}
+
+	if creds, ok := stored[defaultCredentialsKey]; ok {
+		cfg.AccessToken = creds.AccessToken
+		cfg.RefreshToken = creds.RefreshToken
+		cfg.ClientSecret = creds.ClientSecret
+	}
+	for ref := range cfg.Tokens {
+		if creds, ok := stored[tokensCredentialsKeyPrefix+ref]; ok {
+			cfg.Tokens[ref] = TokenSet(creds)
+		}
+	}
+	for name, profile := range cfg.Profiles {
+		if creds, ok := stored[profilesCredentialsKeyPrefix+name]; ok {
+			profile.AccessToken = creds.AccessToken
+			profile.RefreshToken = creds.RefreshToken
+			profile.ClientSecret = creds.ClientSecret
+			cfg.Profiles[name] = profile
+		}
+	}
+
+	cfg.storedIn = name
+	cfg.storedCredentials = stored
+	return nil
+}
+
+// saveCredentials saves the tokens and client secrets of the config which changed since they were loaded
+// to the credential store, in a single operation, and returns the config to write to the config file, without them.
+// When another store was selected, all the credentials are saved to it, or to the config file for the plaintext store.
+func saveCredentials(cfg *Config, file string) (*Config, error) {
+	name := targetStoreName(cfg)
+	store, err := credentials.NewStore(name, filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+
+	saved := *cfg
+	// the store is recorded, so that the credentials are found when another store is selected
+	saved.CredentialStore = name
+	if store == nil {
+		return &saved, nil
+	}
+
+	var changes map[string]credentials.Credentials
+	if sameStore(name, cfg.storedIn) {
+		changes = changedCredentials(cfg.storedCredentials, configCredentials(cfg))
+	} else {
+		changes = changedCredentials(nil, configCredentials(cfg))
+	}
+	if len(changes) > 0 {
+		if err = store.Update(changes); err != nil {
+			return nil, err
+		}
+	}
+
+	saved.AccessToken = ""
+	saved.RefreshToken = ""
+	saved.ClientSecret = ""
+	if cfg.Tokens != nil {
+		// the references are kept in the config file, so that their credentials can be loaded
+		saved.Tokens = make(map[string]TokenSet, len(cfg.Tokens))
+		for ref := range cfg.Tokens {
+			saved.Tokens[ref] = TokenSet{}
+		}
+	}
+	if cfg.Profiles != nil {
+		saved.Profiles = make(map[string]Profile, len(cfg.Profiles))
+		for name, profile := range cfg.Profiles {
+			profile.AccessToken = ""
+			profile.RefreshToken = ""
+			profile.ClientSecret = ""
+			saved.Profiles[name] = profile
+		}
+	}
+
+	return &saved, nil
+}
+
+// savedCredentials records that the credentials of the config were saved, once the config file is written.
+// When they were moved from another store, they are erased from it.
+func savedCredentials(cfg *Config, file string) error {
+	name := targetStoreName(cfg)
+	previous, previousCreds := cfg.storedIn, cfg.storedCredentials
+
+	cfg.storedIn = name
+	cfg.storedCredentials = nil
+	if !isPlaintextStore(name) {
+		cfg.storedCredentials = changedCredentials(nil, configCredentials(cfg))
+	}
+
+	if sameStore(name, previous) || isPlaintextStore(previous) || len(previousCreds) == 0 {
+		return nil
+	}
+	store, err := credentials.NewStore(previous, filepath.Dir(file))
+	if err != nil {
+		return err
+	}
+	erased := make(map[string]credentials.Credentials, len(previousCreds))
+	for key := range previousCreds {
+		erased[key] = credentials.Credentials{}
+	}
+	return store.Update(erased)
+}
+
+// credentialKeys returns the keys of all the credentials of the config
+func credentialKeys(cfg *Config) []string {
+	keys := []string{defaultCredentialsKey}
+	for ref := range cfg.Tokens {
+		keys = append(keys, tokensCredentialsKeyPrefix+ref)
+	}
+	for name := range cfg.Profiles {
+		keys = append(keys, profilesCredentialsKeyPrefix+name)
+	}
+	return keys
+}
+
+// configCredentials returns the credentials of the config, by key
+func configCredentials(cfg *Config) map[string]credentials.Credentials {
+	creds := map[string]credentials.Credentials{
+		defaultCredentialsKey: {
+			AccessToken:  cfg.AccessToken,
+			RefreshToken: cfg.RefreshToken,
+			ClientSecret: cfg.ClientSecret,
+		},
+	}
+	for ref, tokens := range cfg.Tokens {
+		creds[tokensCredentialsKeyPrefix+ref] = credentials.Credentials(tokens)
+	}
+	for name, profile := range cfg.Profiles {
+		creds[profilesCredentialsKeyPrefix+name] = credentials.Credentials{
+			AccessToken:  profile.AccessToken,
+			RefreshToken: profile.RefreshToken,
+			ClientSecret: profile.ClientSecret,
+		}
+	}
+	return creds
+}
+
+// changedCredentials returns the credentials to store so that the stored credentials become the current ones.
+// Empty credentials erase the credentials which are no longer in the config.
+func changedCredentials(stored map[string]credentials.Credentials, current map[string]credentials.Credentials) map[string]credentials.Credentials {
+	changes := make(map[string]credentials.Credentials)
+	for key, creds := range current {
+		if creds != stored[key] {
+			changes[key] = creds
+		}
+	}
+	for key := range stored {
+		if _, ok := current[key]; !ok {
+			changes[key] = credentials.Credentials{}
+		}
+	}
+	return changes
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/lockedfile"
 )
 
 // NewFile creates a new config type
@@ -21,8 +24,12 @@ const errorFormat = "%v: %w"
 
 const EnvName = "RHOASCONFIG"
 
+// ErrCredentials is returned when the credentials of the configuration cannot be loaded from its credential store
+var ErrCredentials = errors.New("unable to load credentials")
+
 // Load loads the configuration from the configuration file. If the configuration file doesn't exist
 // it will return an empty configuration object.
+// The tokens and client secrets are loaded from the credential store of the configuration.
 func (c *File) Load() (*Config, error) {
 	file, err := c.Location()
 	if err != nil {
@@ -45,33 +52,88 @@ func (c *File) Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, "unable to parse config", err)
 	}
+	if err = loadCredentials(&cfg, file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCredentials, err)
+	}
 	return &cfg, nil
 }
 
 // Save saves the given configuration to the configuration file.
+// The file is replaced atomically while holding the lock of the configuration file.
 func (c *File) Save(cfg *Config) error {
 	file, err := c.Location()
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err = c.ensureDir(file); err != nil {
+		return err
+	}
+	unlock, err := lockedfile.Lock(file)
 	if err != nil {
-		return fmt.Errorf("%v: %w", "unable to marshal config", err)
+		return err
+	}
+	defer unlock() // nolint:errcheck
+	return c.write(file, cfg)
+}
+
+// Update loads the configuration, applies update to it and saves the result
+// while holding the lock of the configuration file, so that the changes of concurrent
+// processes are not lost. If the configuration file doesn't exist, update is applied to an empty configuration.
+func (c *File) Update(update func(cfg *Config) error) error {
+	file, err := c.Location()
+	if err != nil {
+		return err
+	}
+	if err = c.ensureDir(file); err != nil {
+		return err
+	}
+	unlock, err := lockedfile.Lock(file)
+	if err != nil {
+		return err
+	}
+	defer unlock() // nolint:errcheck
+
+	cfg, err := c.Load()
+	if os.IsNotExist(err) {
+		cfg, err = &Config{}, nil
 	}
-	rhoasCfgDir, err := DefaultDir()
 	if err != nil {
 		return err
 	}
-	if _, err = os.Stat(rhoasCfgDir); os.IsNotExist(err) {
-		err = os.Mkdir(rhoasCfgDir, 0o700)
+	if err = update(cfg); err != nil {
+		return err
+	}
+	return c.write(file, cfg)
+}
+
+// ensureDir creates the parent directory of the file
+func (c *File) ensureDir(file string) error {
+	dir := filepath.Dir(file)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		err = os.Mkdir(dir, 0o700)
 		if err != nil {
 			return err
 		}
 	}
-	err = os.WriteFile(file, data, 0o600)
+	return nil
+}
+
+func (c *File) write(file string, cfg *Config) error {
+	saved, err := saveCredentials(cfg, file)
+	if err != nil {
+		return fmt.Errorf(errorFormat, "unable to save credentials", err)
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%v: %w", "unable to marshal config", err)
+	}
+	err = lockedfile.WriteFile(file, data, 0o600)
 	if err != nil {
 		return fmt.Errorf(errorFormat, "unable to save config", err)
 	}
+	if err = savedCredentials(cfg, file); err != nil {
+		return fmt.Errorf(errorFormat, "unable to erase the credentials moved from the previous credential store", err)
+	}
 	return nil
 }
 
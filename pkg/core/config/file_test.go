@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/credentials"
+)
+
+func TestFileUpdateConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvName, filepath.Join(dir, "config.json"))
+
+	cfgFile := &File{}
+
+	const writers = 50
+
+	// readers must never see a partially written file while the writers update it
+	stop := make(chan struct{})
+	readErrs := make(chan error, 1)
+	go func() {
+		defer close(readErrs)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if _, err := cfgFile.Load(); err != nil && !os.IsNotExist(err) {
+				readErrs <- err
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writers)
+	for i := 0; i < writers; i++ {
+		// processes starting without a config file create it concurrently with the writers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cfgFile.Update(func(*Config) error { return nil })
+		}()
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- cfgFile.Update(func(cfg *Config) error {
+				if cfg.Tokens == nil {
+					cfg.Tokens = make(map[string]TokenSet)
+				}
+				cfg.Tokens[fmt.Sprintf("ref-%d", i)] = TokenSet{AccessToken: fmt.Sprintf("token-%d", i)}
+				cfg.LastUpdated++
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(stop)
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := <-readErrs; err != nil {
+		t.Fatalf("Load() while updating = %v", err)
+	}
+
+	cfg, err := cfgFile.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LastUpdated != writers || len(cfg.Tokens) != writers {
+		t.Errorf("Update() lost updates: counter = %v, tokens = %v, want %v", cfg.LastUpdated, len(cfg.Tokens), writers)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != "config.json" && name != "config.json.lock" {
+			t.Errorf("unexpected file %v left in the config directory", name)
+		}
+	}
+}
+
+func TestFileCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvName, filepath.Join(dir, "config.json"))
+	t.Setenv(credentials.PassphraseEnvName, "passphrase")
+
+	cfgFile := &File{}
+	cfg := &Config{
+		AccessToken:     "access",
+		RefreshToken:    "refresh",
+		CredentialStore: credentials.EncryptedFileStore,
+		Tokens: map[string]TokenSet{
+			"staging": {ClientSecret: "secret"},
+		},
+	}
+	if err := cfgFile.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AccessToken != "access" {
+		t.Error("Save() modified the saved config")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"access", "refresh", "secret"} {
+		if strings.Contains(string(data), `"`+secret+`"`) {
+			t.Errorf("the config file contains the credential %q: %s", secret, data)
+		}
+	}
+
+	loaded, err := cfgFile.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.AccessToken != "access" || loaded.RefreshToken != "refresh" || loaded.Tokens["staging"].ClientSecret != "secret" {
+		t.Errorf("Load() = %+v, want the credentials from the encrypted file", loaded)
+	}
+}
+
+func TestFileCredentialStoreSwitch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvName, filepath.Join(dir, "config.json"))
+	t.Setenv(credentials.PassphraseEnvName, "passphrase")
+
+	cfgFile := &File{}
+	err := cfgFile.Save(&Config{
+		RefreshToken:    "refresh",
+		CredentialStore: credentials.EncryptedFileStore,
+		Profiles: map[string]Profile{
+			"dev":  {RefreshToken: "dev-refresh"},
+			"prod": {RefreshToken: "prod-refresh"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the credentials of a deleted profile are erased from the store
+	err = cfgFile.Update(func(cfg *Config) error {
+		delete(cfg.Profiles, "prod")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := credentials.NewEncryptedFileWithPassphrase(filepath.Join(dir, credentials.EncryptedFileName), "passphrase")
+	stored, err := store.Get([]string{"default", "profiles/dev", "profiles/prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 || stored["profiles/dev"].RefreshToken != "dev-refresh" {
+		t.Errorf("stored credentials = %v, want the credentials of default and dev", stored)
+	}
+
+	// switching back to the plaintext store moves the credentials to the config file
+	err = cfgFile.Update(func(cfg *Config) error {
+		cfg.CredentialStore = credentials.PlaintextStore
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, err = store.Get([]string{"default", "profiles/dev"}); err != nil || len(stored) != 0 {
+		t.Errorf("stored credentials = %v, %v, want them to be erased from the encrypted file", stored, err)
+	}
+
+	t.Setenv(credentials.PassphraseEnvName, "")
+	loaded, err := cfgFile.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.RefreshToken != "refresh" || loaded.Profiles["dev"].RefreshToken != "dev-refresh" {
+		t.Errorf("Load() = %+v, want the credentials in the config file", loaded)
+	}
+}
+
+func TestChangedCredentials(t *testing.T) {
+	stored := map[string]credentials.Credentials{
+		"default":      {RefreshToken: "refresh"},
+		"profiles/dev": {RefreshToken: "dev"},
+	}
+	current := map[string]credentials.Credentials{
+		"default":        {RefreshToken: "refresh"},
+		"profiles/prod":  {RefreshToken: "prod"},
+		"tokens/staging": {},
+	}
+
+	got := changedCredentials(stored, current)
+	want := map[string]credentials.Credentials{
+		"profiles/dev":  {},
+		"profiles/prod": {RefreshToken: "prod"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedCredentials() = %v, want %v", got, want)
+	}
+	if got = changedCredentials(current, current); len(got) != 0 {
+		t.Errorf("changedCredentials() of unchanged credentials = %v, want none", got)
+	}
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+)
+
+// DefaultProfileName is the name of the login profile stored in the top-level fields of the config
+const DefaultProfileName = "default"
+
+// ProfileEnvName is the environment variable which selects the login profile
+const ProfileEnvName = "APICR_PROFILE"
+
+// Profile is a named login, with its own tokens and endpoints
+type Profile struct {
+	AccessToken  string   `json:"access_token,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	APIUrl       string   `json:"api_url,omitempty"`
+	AuthURL      string   `json:"auth_url,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	Insecure     bool     `json:"insecure,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	CAFile       string   `json:"ca_file,omitempty"`
+	ClientCert   string   `json:"client_cert,omitempty"`
+	ClientKey    string   `json:"client_key,omitempty"`
+	ProxyURL     string   `json:"proxy_url,omitempty"`
+	NoProxy      string   `json:"no_proxy,omitempty"`
+}
+
+// IsEmpty reports whether the profile has no login
+func (p *Profile) IsEmpty() bool {
+	return p.AccessToken == "" && p.RefreshToken == "" && p.ClientSecret == "" &&
+		p.APIUrl == "" && p.AuthURL == "" && p.ClientID == "" && !p.Insecure && len(p.Scopes) == 0 &&
+		p.CAFile == "" && p.ClientCert == "" && p.ClientKey == "" && p.ProxyURL == "" && p.NoProxy == ""
+}
+
+// ProfileOf returns the login profile stored in the top-level fields of cfg
+func ProfileOf(cfg *Config) Profile {
+	return Profile{
+		AccessToken:  cfg.AccessToken,
+		RefreshToken: cfg.RefreshToken,
+		ClientSecret: cfg.ClientSecret,
+		APIUrl:       cfg.APIUrl,
+		AuthURL:      cfg.AuthURL,
+		ClientID:     cfg.ClientID,
+		Insecure:     cfg.Insecure,
+		Scopes:       cfg.Scopes,
+		CAFile:       cfg.CAFile,
+		ClientCert:   cfg.ClientCert,
+		ClientKey:    cfg.ClientKey,
+		ProxyURL:     cfg.ProxyURL,
+		NoProxy:      cfg.NoProxy,
+	}
+}
+
+// applyProfile sets the login of the profile in the top-level fields of cfg
+func applyProfile(cfg *Config, p Profile) {
+	cfg.AccessToken = p.AccessToken
+	cfg.RefreshToken = p.RefreshToken
+	cfg.ClientSecret = p.ClientSecret
+	cfg.APIUrl = p.APIUrl
+	cfg.AuthURL = p.AuthURL
+	cfg.ClientID = p.ClientID
+	cfg.Insecure = p.Insecure
+	cfg.Scopes = p.Scopes
+	cfg.CAFile = p.CAFile
+	cfg.ClientCert = p.ClientCert
+	cfg.ClientKey = p.ClientKey
+	cfg.ProxyURL = p.ProxyURL
+	cfg.NoProxy = p.NoProxy
+}
+
+// ActiveProfile returns the name of the login profile to use: the selected profile,
+// or the current profile of the config. It returns an empty name for the default profile.
+func ActiveProfile(cfg *Config, selected string) string {
+	name := selected
+	if name == "" {
+		name = cfg.CurrentProfile
+	}
+	if name == DefaultProfileName {
+		return ""
+	}
+	return name
+}
+
+// SelectedProfile returns the profile set with the APICR_PROFILE environment variable
+func SelectedProfile() string {
+	return os.Getenv(ProfileEnvName)
+}
+
+// ProfileConfig is a config which applies the active login profile on top of the config file.
+// Logins saved while a named profile is active are stored in that profile,
+// so that every profile keeps its own tokens and endpoints.
+type ProfileConfig struct {
+	Config IConfig
+	// Selected returns the profile selected for the command, which takes precedence over the current profile
+	Selected func() string
+}
+
+// NewProfileConfig creates a config driven by the active login profile
+func NewProfileConfig(cfg IConfig, selected func() string) IConfig {
+	return &ProfileConfig{
+		Config:   cfg,
+		Selected: selected,
+	}
+}
+
+// Load loads the config file and applies the active profile.
+// A profile which does not exist yet has no login.
+func (p *ProfileConfig) Load() (*Config, error) {
+	cfg, err := p.Config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if name := ActiveProfile(cfg, p.Selected()); name != "" {
+		applyProfile(cfg, cfg.Profiles[name])
+	}
+
+	return cfg, nil
+}
+
+// Save saves the config file. The login of the active profile is saved to that profile.
+func (p *ProfileConfig) Save(cfg *Config) error {
+	return p.Update(func(current *Config) error {
+		*current = *cfg
+		return nil
+	})
+}
+
+// Update applies update to the config of the active profile and saves it like Save,
+// while holding the lock of the config file
+func (p *ProfileConfig) Update(update func(cfg *Config) error) error {
+	return p.Config.Update(func(stored *Config) error {
+		name := ActiveProfile(stored, p.Selected())
+		if name == "" {
+			return update(stored)
+		}
+
+		_, existed := stored.Profiles[name]
+		cfg := *stored
+		applyProfile(&cfg, stored.Profiles[name])
+		if err := update(&cfg); err != nil {
+			return err
+		}
+
+		saved := cfg
+		applyProfile(&saved, ProfileOf(stored))
+
+		// a profile removed by the update is not saved again, and a new profile is only saved with a login
+		profile := ProfileOf(&cfg)
+		_, kept := cfg.Profiles[name]
+		if kept || (!existed && !profile.IsEmpty()) {
+			saved.Profiles = make(map[string]Profile, len(cfg.Profiles)+1)
+			for n, p := range cfg.Profiles {
+				saved.Profiles[n] = p
+			}
+			saved.Profiles[name] = profile
+		}
+
+		*stored = saved
+		return nil
+	})
+}
+
+// Remove removes the config file
+func (p *ProfileConfig) Remove() error {
+	return p.Config.Remove()
+}
+
+// Location returns the location of the config file
+func (p *ProfileConfig) Location() (string, error) {
+	return p.Config.Location()
+}
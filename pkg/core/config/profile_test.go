@@ -0,0 +1,109 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileConfig(t *testing.T) {
+	t.Setenv(EnvName, filepath.Join(t.TempDir(), "config.json"))
+
+	cfgFile := &File{}
+	err := cfgFile.Save(&Config{
+		APIUrl:      "https://api.default",
+		AccessToken: "default-token",
+		Profiles: map[string]Profile{
+			"partner": {APIUrl: "https://api.partner", AccessToken: "partner-token", Scopes: []string{"openid"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var selected string
+	profileConfig := NewProfileConfig(cfgFile, func() string { return selected })
+
+	cfg, err := profileConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIUrl != "https://api.default" || cfg.AccessToken != "default-token" {
+		t.Errorf("Load() = %v %v, want the default profile", cfg.APIUrl, cfg.AccessToken)
+	}
+
+	selected = "partner"
+	cfg, err = profileConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIUrl != "https://api.partner" || cfg.AccessToken != "partner-token" || len(cfg.Scopes) != 1 {
+		t.Errorf("Load() = %v %v %v, want the partner profile", cfg.APIUrl, cfg.AccessToken, cfg.Scopes)
+	}
+
+	// logging out of the selected profile keeps the login of the other profiles
+	err = profileConfig.Update(func(cfg *Config) error {
+		cfg.AccessToken = ""
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// logging in to a new profile creates it
+	selected = "staging"
+	err = profileConfig.Update(func(cfg *Config) error {
+		cfg.APIUrl = "https://api.staging"
+		cfg.AccessToken = "staging-token"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := cfgFile.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.AccessToken != "default-token" || stored.APIUrl != "https://api.default" {
+		t.Errorf("stored default profile = %v %v, want it unchanged", stored.APIUrl, stored.AccessToken)
+	}
+	if p := stored.Profiles["partner"]; p.AccessToken != "" || p.APIUrl != "https://api.partner" {
+		t.Errorf("stored partner profile = %v %v, want it logged out", p.APIUrl, p.AccessToken)
+	}
+	if p := stored.Profiles["staging"]; p.AccessToken != "staging-token" || p.APIUrl != "https://api.staging" {
+		t.Errorf("stored staging profile = %v %v, want it logged in", p.APIUrl, p.AccessToken)
+	}
+
+	// the current profile applies when no profile is selected
+	selected = ""
+	stored.CurrentProfile = "staging"
+	if err = cfgFile.Save(stored); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = profileConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AccessToken != "staging-token" {
+		t.Errorf("Load() access token = %v, want the current profile", cfg.AccessToken)
+	}
+
+	// a selected profile which does not exist has no login, and is not saved
+	selected = "unknown"
+	cfg, err = profileConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AccessToken != "" || cfg.APIUrl != "" {
+		t.Errorf("Load() = %v %v, want no login", cfg.APIUrl, cfg.AccessToken)
+	}
+	if err = profileConfig.Update(func(cfg *Config) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err = cfgFile.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored.Profiles["unknown"]; ok {
+		t.Error("Update() saved an empty profile")
+	}
+}
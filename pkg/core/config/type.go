@@ -1,5 +1,7 @@
 package config
 
+import "github.com/apicurio/apicurio-cli/pkg/core/credentials"
+
 // IConfig is an interface which describes the functions
 // needed to read/write from a config
 //
@@ -7,22 +9,52 @@ package config
 type IConfig interface {
 	Load() (*Config, error)
 	Save(config *Config) error
+	Update(update func(config *Config) error) error
 	Remove() error
 	Location() (string, error)
 }
 
 // Config is a type which describes the properties which can be in the config
 type Config struct {
-	AccessToken  string           `json:"access_token,omitempty" doc:"Bearer access token."`
-	RefreshToken string           `json:"refresh_token,omitempty" doc:"Offline or refresh token."`
-	Services     ServiceConfigMap `json:"services,omitempty"`
-	APIUrl       string           `json:"api_url,omitempty" doc:"URL of the API gateway. The value can be the complete URL or an alias. The valid aliases are 'production', 'staging' and 'integration'."`
-	AuthURL      string           `json:"auth_url,omitempty" doc:"URL of the authentication server"`
-	ClientID     string           `json:"client_id,omitempty" doc:"OpenID client identifier."`
-	Insecure     bool             `json:"insecure,omitempty" doc:"Enables insecure communication with the server. This disables verification of TLS certificates and host names."`
-	Scopes       []string         `json:"scopes,omitempty" doc:"OpenID scope. If this option is used it will replace completely the default scopes. Can be repeated multiple times to specify multiple scopes."`
-	Telemetry    string           `json:"telemetry,omitempty" doc:"Flag used to enable telemetry for user."`
-	LastUpdated  int64            `json:"last_updated,omitempty" doc:"Timestamp of the last update cli"`
+	AccessToken     string              `json:"access_token,omitempty" doc:"Bearer access token."`
+	RefreshToken    string              `json:"refresh_token,omitempty" doc:"Offline or refresh token."`
+	Services        ServiceConfigMap    `json:"services,omitempty"`
+	APIUrl          string              `json:"api_url,omitempty" doc:"URL of the API gateway. The value can be the complete URL or an alias. The valid aliases are 'production', 'staging' and 'integration'."`
+	AuthURL         string              `json:"auth_url,omitempty" doc:"URL of the authentication server"`
+	ClientID        string              `json:"client_id,omitempty" doc:"OpenID client identifier."`
+	ClientSecret    string              `json:"client_secret,omitempty" doc:"Secret of the service account used to log in with the client credentials grant."`
+	Insecure        bool                `json:"insecure,omitempty" doc:"Enables insecure communication with the server. This disables verification of TLS certificates and host names."`
+	Scopes          []string            `json:"scopes,omitempty" doc:"OpenID scope. If this option is used it will replace completely the default scopes. Can be repeated multiple times to specify multiple scopes."`
+	CAFile          string              `json:"ca_file,omitempty" doc:"PEM file of the certificate authorities trusted in addition to the ones of the system."`
+	ClientCert      string              `json:"client_cert,omitempty" doc:"PEM file of the client certificate used for mutual TLS."`
+	ClientKey       string              `json:"client_key,omitempty" doc:"PEM file of the private key of the client certificate. Defaults to the client certificate file."`
+	ProxyURL        string              `json:"proxy_url,omitempty" doc:"URL of the proxy used for the requests. Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables."`
+	NoProxy         string              `json:"no_proxy,omitempty" doc:"Comma-separated hosts, domains and CIDR ranges which are not reached through the proxy."`
+	Retries         *int                `json:"retries,omitempty" doc:"Number of times a request is sent again after a transient failure. Defaults to 3."`
+	RetryMaxWait    string              `json:"retry_max_wait,omitempty" doc:"Longest wait before a retry, such as '30s' or '2m'. Defaults to 30s."`
+	RetryPOST       bool                `json:"retry_post,omitempty" doc:"Allows POST requests to be retried, which may create the same resource twice."`
+	HTTPLogLevel    string              `json:"http_log_level,omitempty" doc:"HTTP requests logged in debug mode. The valid levels are 'errors' (the default) and 'all'."`
+	HTTPLogCurl     bool                `json:"http_log_curl,omitempty" doc:"Logs the HTTP requests as equivalent curl commands in debug mode."`
+	CacheTTL        string              `json:"cache_ttl,omitempty" doc:"How long the metadata of the Service Registry instances and the artifact types are cached, such as '5m' or '1h'. Defaults to 5m, and '0' disables it. Artifact content is cached until it is evicted."`
+	CacheMaxSize    string              `json:"cache_max_size,omitempty" doc:"Size the cache can grow to before its least recently used responses are evicted, such as '100MB'. Defaults to 100MB, and '0' removes the limit."`
+	Telemetry       string              `json:"telemetry,omitempty" doc:"Flag used to enable telemetry for user."`
+	LastUpdated     int64               `json:"last_updated,omitempty" doc:"Timestamp of the last update cli"`
+	Tokens          map[string]TokenSet `json:"tokens,omitempty" doc:"Tokens of the context environments, by token reference."`
+	Profiles        map[string]Profile  `json:"profiles,omitempty" doc:"Named login profiles, each with its own tokens and endpoints."`
+	CurrentProfile  string              `json:"current_profile,omitempty" doc:"Name of the login profile used when no profile is selected."`
+	CredentialStore string              `json:"credential_store,omitempty" doc:"Store of the tokens and client secrets. The valid stores are 'plaintext' (the config file), 'encrypted-file' and 'helper:<name>'. The credentials are moved when another store is selected."`
+
+	// storedIn is the credential store the credentials were loaded from, and storedCredentials are the credentials
+	// it holds, so that only the credentials which changed are saved
+	storedIn          string
+	storedCredentials map[string]credentials.Credentials
+}
+
+// TokenSet holds the tokens of a context environment
+type TokenSet struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
 }
 
 // ServiceConfigMap is a map of configs for the application services
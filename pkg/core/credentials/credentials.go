@@ -0,0 +1,71 @@
+// Package credentials stores the secrets of logins outside of the config file.
+package credentials
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Names of the credential stores
+const (
+	// PlaintextStore keeps the credentials in the config file, it is the default store
+	PlaintextStore = "plaintext"
+	// EncryptedFileStore keeps the credentials in a file encrypted with a passphrase or a key
+	EncryptedFileStore = "encrypted-file"
+	// HelperStorePrefix prefixes the name of an external credential helper, as in "helper:pass"
+	HelperStorePrefix = "helper:"
+)
+
+// StoreEnvName is the environment variable which selects the credential store, overriding the config file.
+// The credentials are moved to the selected store when the config is saved.
+const StoreEnvName = "APICR_CREDENTIAL_STORE"
+
+// Credentials are the secrets of a login
+type Credentials struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// IsEmpty reports whether there are no credentials
+func (c *Credentials) IsEmpty() bool {
+	return c == nil || *c == Credentials{}
+}
+
+// Store is a store of credentials, by key. The credentials of several keys are read
+// and written in a single operation, as the stores can be slow to open.
+type Store interface {
+	// Get returns the credentials of the keys, leaving out the keys without credentials
+	Get(keys []string) (map[string]Credentials, error)
+	// Update stores the credentials of the keys, empty credentials erasing them
+	Update(creds map[string]Credentials) error
+}
+
+// NewStore creates the credential store with the given name. The encrypted file is created in dir.
+// It returns nil for the plaintext store, as the credentials then stay in the config file.
+func NewStore(name string, dir string) (Store, error) {
+	if err := ValidateStoreName(name); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case name == "" || name == PlaintextStore:
+		return nil, nil
+	case name == EncryptedFileStore:
+		return NewEncryptedFile(filepath.Join(dir, EncryptedFileName))
+	default:
+		return NewHelper(strings.TrimPrefix(name, HelperStorePrefix)), nil
+	}
+}
+
+// ValidateStoreName checks that name is the name of a credential store
+func ValidateStoreName(name string) error {
+	switch {
+	case name == "" || name == PlaintextStore || name == EncryptedFileStore:
+		return nil
+	case strings.HasPrefix(name, HelperStorePrefix) && len(name) > len(HelperStorePrefix):
+		return nil
+	}
+	return fmt.Errorf("unknown credential store %q, valid stores are %q, %q and %q", name, PlaintextStore, EncryptedFileStore, HelperStorePrefix+"<name>")
+}
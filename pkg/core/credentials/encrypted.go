@@ -0,0 +1,243 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/lockedfile"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedFileName is the name of the encrypted credentials file
+const EncryptedFileName = "credentials.enc"
+
+// Environment variables with the secret of the encrypted file.
+// The key is a base64 encoded 256-bit key, and takes precedence over the passphrase.
+const (
+	KeyEnvName        = "APICR_CREDENTIALS_KEY"
+	PassphraseEnvName = "APICR_CREDENTIALS_PASSPHRASE"
+)
+
+const (
+	encryptedFileVersion = 1
+	kdfNone              = "none"
+	kdfScrypt            = "scrypt"
+	keySize              = 32
+	saltSize             = 16
+)
+
+// scrypt cost parameters recommended for interactive logins
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// EncryptedFile stores the credentials in a file encrypted with AES-GCM,
+// using a key or a key derived from a passphrase with scrypt
+type EncryptedFile struct {
+	Path string
+
+	key        []byte
+	passphrase string
+	// derived caches the key derived from the passphrase for a salt
+	derived map[string][]byte
+}
+
+// encryptedFile is the content of the encrypted credentials file
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewEncryptedFile creates a store in the encrypted file at path,
+// using the key or passphrase set in the environment
+func NewEncryptedFile(path string) (*EncryptedFile, error) {
+	if encodedKey := os.Getenv(KeyEnvName); encodedKey != "" {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%v must be a base64 encoded %v-byte key", KeyEnvName, keySize)
+		}
+		return &EncryptedFile{Path: path, key: key}, nil
+	}
+
+	if passphrase := os.Getenv(PassphraseEnvName); passphrase != "" {
+		return NewEncryptedFileWithPassphrase(path, passphrase), nil
+	}
+
+	return nil, fmt.Errorf("the %q credential store requires the %v or %v environment variable", EncryptedFileStore, PassphraseEnvName, KeyEnvName)
+}
+
+// NewEncryptedFileWithPassphrase creates a store in the encrypted file at path, encrypted with the passphrase
+func NewEncryptedFileWithPassphrase(path string, passphrase string) *EncryptedFile {
+	return &EncryptedFile{Path: path, passphrase: passphrase, derived: make(map[string][]byte)}
+}
+
+// Get returns the credentials of the keys, decrypting the file once
+func (e *EncryptedFile) Get(keys []string) (map[string]Credentials, error) {
+	entries, _, err := e.read()
+	if err != nil {
+		return nil, err
+	}
+	creds := make(map[string]Credentials, len(keys))
+	for _, key := range keys {
+		if entry, ok := entries[key]; ok && !entry.IsEmpty() {
+			creds[key] = entry
+		}
+	}
+	return creds, nil
+}
+
+// Update stores the credentials of the keys, replacing the file atomically
+func (e *EncryptedFile) Update(creds map[string]Credentials) error {
+	return e.update(func(entries map[string]Credentials) {
+		for key, entry := range creds {
+			if entry.IsEmpty() {
+				delete(entries, key)
+				continue
+			}
+			entries[key] = entry
+		}
+	})
+}
+
+func (e *EncryptedFile) update(apply func(entries map[string]Credentials)) error {
+	unlock, err := lockedfile.Lock(e.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock() // nolint:errcheck
+
+	entries, salt, err := e.read()
+	if err != nil {
+		return err
+	}
+	apply(entries)
+
+	return e.write(entries, salt)
+}
+
+// read decrypts the entries of the file, and returns the salt of the passphrase
+func (e *EncryptedFile) read() (map[string]Credentials, []byte, error) {
+	entries := make(map[string]Credentials)
+
+	data, err := os.ReadFile(e.Path)
+	if os.IsNotExist(err) {
+		return entries, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the credentials file: %w", err)
+	}
+
+	var file encryptedFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the credentials file: %w", err)
+	}
+	if file.Version != encryptedFileVersion {
+		return nil, nil, fmt.Errorf("unsupported credentials file version %v", file.Version)
+	}
+	if (file.KDF == kdfNone) != (e.key != nil) {
+		return nil, nil, fmt.Errorf("the credentials file %v is encrypted with a %v, set it with the %v environment variable", e.Path, secretKind(file.KDF), secretEnvName(file.KDF))
+	}
+
+	gcm, err := e.cipher(file.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, errors.New("unable to decrypt the credentials file, the passphrase or key is wrong")
+	}
+	if err = json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the decrypted credentials: %w", err)
+	}
+
+	return entries, file.Salt, nil
+}
+
+// write encrypts the entries to the file. The salt of an existing file is kept,
+// so that the key is only derived once, while every write uses a new nonce.
+func (e *EncryptedFile) write(entries map[string]Credentials, salt []byte) error {
+	file := encryptedFile{
+		Version: encryptedFileVersion,
+		KDF:     kdfNone,
+	}
+	if e.key == nil {
+		file.KDF = kdfScrypt
+		file.Salt = salt
+		if file.Salt == nil {
+			file.Salt = make([]byte, saltSize)
+			if _, err := rand.Read(file.Salt); err != nil {
+				return err
+			}
+		}
+	}
+
+	gcm, err := e.cipher(file.Salt)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = lockedfile.WriteFile(e.Path, data, 0o600); err != nil {
+		return fmt.Errorf("unable to save the credentials file: %w", err)
+	}
+	return nil
+}
+
+func (e *EncryptedFile) cipher(salt []byte) (cipher.AEAD, error) {
+	key := e.key
+	if key == nil {
+		var ok bool
+		if key, ok = e.derived[string(salt)]; !ok {
+			var err error
+			key, err = scrypt.Key([]byte(e.passphrase), salt, scryptN, scryptR, scryptP, keySize)
+			if err != nil {
+				return nil, err
+			}
+			e.derived[string(salt)] = key
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func secretKind(kdf string) string {
+	if kdf == kdfNone {
+		return "key"
+	}
+	return "passphrase"
+}
+
+func secretEnvName(kdf string) string {
+	if kdf == kdfNone {
+		return KeyEnvName
+	}
+	return PassphraseEnvName
+}
@@ -0,0 +1,79 @@
+package credentials
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EncryptedFileName)
+	store := NewEncryptedFileWithPassphrase(path, "correct horse")
+
+	creds := Credentials{AccessToken: "access", RefreshToken: "refresh"}
+	err := store.Update(map[string]Credentials{
+		"default":        creds,
+		"tokens/staging": {ClientSecret: "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "refresh") || strings.Contains(string(data), "secret") {
+		t.Errorf("the credentials file contains plaintext credentials: %s", data)
+	}
+
+	got, err := NewEncryptedFileWithPassphrase(path, "correct horse").Get([]string{"default", "profiles/unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["default"] != creds {
+		t.Errorf("Get() = %v, want only the credentials of default", got)
+	}
+
+	if _, err = NewEncryptedFileWithPassphrase(path, "wrong").Get([]string{"default"}); err == nil {
+		t.Error("Get() with a wrong passphrase should fail")
+	}
+
+	if err = store.Update(map[string]Credentials{"default": {}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.Get([]string{"default", "tokens/staging"}); err != nil || len(got) != 1 || got["tokens/staging"].ClientSecret != "secret" {
+		t.Errorf("Get() after erasing default = %v, %v, want only the other credentials to be kept", got, err)
+	}
+}
+
+func TestNewEncryptedFileWithKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EncryptedFileName)
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", keySize)))
+
+	t.Setenv(KeyEnvName, key)
+	store, err := NewEncryptedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Update(map[string]Credentials{"default": {AccessToken: "access"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(KeyEnvName, "")
+	t.Setenv(PassphraseEnvName, "passphrase")
+	store, err = NewEncryptedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get([]string{"default"}); err == nil || !strings.Contains(err.Error(), KeyEnvName) {
+		t.Errorf("Get() error = %v, want the key to be required", err)
+	}
+
+	t.Setenv(PassphraseEnvName, "")
+	if _, err = NewEncryptedFile(path); err == nil {
+		t.Error("NewEncryptedFile() without a key or passphrase should fail")
+	}
+}
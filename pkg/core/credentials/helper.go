@@ -0,0 +1,99 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// HelperPrefix prefixes the name of a credential helper to get the name of its executable,
+// so that the "pass" helper runs "apicr-credential-pass"
+const HelperPrefix = "apicr-credential-"
+
+// Actions of the credential helper protocol, passed as the last argument of the helper
+const (
+	helperGet    = "get"
+	helperUpdate = "update"
+)
+
+// Helper stores the credentials with an external credential helper.
+// The helper is run once for each action, with the action as argument, and exchanges a JSON object on stdin and stdout:
+//
+//	get:    {"keys": ["default", "profiles/dev"]} -> {"default": {"access_token": "...", "refresh_token": "...", "client_secret": "..."}}
+//	update: {"default": {"access_token": "...", "refresh_token": "...", "client_secret": "..."}, "profiles/dev": {}}
+//
+// The helper leaves out the keys it has no credentials for, and prints an empty object, or nothing, when it has none.
+// Updating a key with an empty object erases its credentials, and the keys which are not sent are kept.
+// A helper which fails exits with a non-zero status and explains why on stderr.
+type Helper struct {
+	Path string
+	Args []string
+}
+
+// helperGetMessage is the object sent to the helper on stdin to get credentials
+type helperGetMessage struct {
+	Keys []string `json:"keys"`
+}
+
+// NewHelper creates a store for the credential helper with the given name or path.
+// A name without a path separator runs the "apicr-credential-<name>" executable from the PATH.
+func NewHelper(name string) *Helper {
+	if !strings.ContainsAny(name, `/\`) {
+		name = HelperPrefix + name
+	}
+	return &Helper{Path: name}
+}
+
+// Get returns the credentials of the keys
+func (h *Helper) Get(keys []string) (map[string]Credentials, error) {
+	out, err := h.run(helperGet, &helperGetMessage{Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make(map[string]Credentials)
+	if len(bytes.TrimSpace(out)) == 0 {
+		return creds, nil
+	}
+	if err = json.Unmarshal(out, &creds); err != nil {
+		return nil, fmt.Errorf("unable to parse the output of the credential helper %v: %w", h.Path, err)
+	}
+	for key, entry := range creds {
+		if entry.IsEmpty() {
+			delete(creds, key)
+		}
+	}
+	return creds, nil
+}
+
+// Update stores the credentials of the keys
+func (h *Helper) Update(creds map[string]Credentials) error {
+	_, err := h.run(helperUpdate, creds)
+	return err
+}
+
+func (h *Helper) run(action string, message interface{}) ([]byte, error) {
+	input, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(append([]string{}, h.Args...), action)
+	// #nosec G204
+	cmd := exec.Command(h.Path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential helper %v %v failed: %v: %v", h.Path, action, err, msg)
+		}
+		return nil, fmt.Errorf("credential helper %v %v failed: %w", h.Path, action, err)
+	}
+
+	return stdout.Bytes(), nil
+}
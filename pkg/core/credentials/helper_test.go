@@ -0,0 +1,127 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const helperProcessEnvName = "GO_WANT_CREDENTIAL_HELPER_PROCESS"
+
+// TestHelperProcess is a credential helper storing the credentials in the JSON file
+// set in the environment, it is run by the helper tests
+func TestHelperProcess(t *testing.T) {
+	path := os.Getenv(helperProcessEnvName)
+	if path == "" {
+		return
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	entries := make(map[string]Credentials)
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &entries)
+	}
+
+	switch action := os.Args[len(os.Args)-1]; action {
+	case helperGet:
+		var message helperGetMessage
+		if err = json.Unmarshal(input, &message); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		found := make(map[string]Credentials)
+		for _, key := range message.Keys {
+			if creds, ok := entries[key]; ok {
+				found[key] = creds
+			}
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(found)
+	case helperUpdate:
+		var updates map[string]Credentials
+		if err = json.Unmarshal(input, &updates); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for key, creds := range updates {
+			if creds.IsEmpty() {
+				delete(entries, key)
+				continue
+			}
+			entries[key] = creds
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "unknown action", action)
+		os.Exit(1)
+	}
+
+	data, _ := json.Marshal(entries)
+	_ = os.WriteFile(path, data, 0o600)
+	os.Exit(0)
+}
+
+func TestHelper(t *testing.T) {
+	t.Setenv(helperProcessEnvName, filepath.Join(t.TempDir(), "helper.json"))
+	helper := &Helper{Path: os.Args[0], Args: []string{"-test.run=TestHelperProcess", "--"}}
+
+	creds := Credentials{AccessToken: "access", RefreshToken: "refresh"}
+	err := helper.Update(map[string]Credentials{
+		"default":      creds,
+		"profiles/dev": {ClientSecret: "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := helper.Get([]string{"default", "profiles/dev", "profiles/unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["default"] != creds || got["profiles/dev"].ClientSecret != "secret" {
+		t.Errorf("Get() = %v, want the credentials of default and profiles/dev", got)
+	}
+
+	if err = helper.Update(map[string]Credentials{"default": {}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = helper.Get([]string{"default", "profiles/dev"}); err != nil || len(got) != 1 {
+		t.Errorf("Get() after erasing default = %v, %v, want only the credentials of profiles/dev", got, err)
+	}
+
+	if _, err = helper.run("unknown", &helperGetMessage{Keys: []string{"default"}}); err == nil {
+		t.Error("run() of an unknown action should fail")
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "Should keep the credentials in the config file by default", store: "", wantNil: true},
+		{name: "Should keep the credentials in the config file", store: PlaintextStore, wantNil: true},
+		{name: "Should run the credential helper", store: "helper:pass"},
+		{name: "Should reject a helper without a name", store: "helper:", wantErr: true},
+		{name: "Should reject an unknown store", store: "keychain", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewStore(tt.store, t.TempDir())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (store == nil) != tt.wantNil {
+				t.Errorf("NewStore() = %v, want nil %v", store, tt.wantNil)
+			}
+		})
+	}
+}
@@ -9,3 +9,22 @@ func NewCastError(v interface{}, t string) error {
 	CastErr = fmt.Errorf(`could not cast %v, to type "%v"`, v, t)
 	return CastErr
 }
+
+// ExitError is an error which makes the CLI exit with a specific exit code
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprint(e.Err)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// NewExitError wraps the error so that the CLI exits with the given exit code
+func NewExitError(err error, code int) *ExitError {
+	return &ExitError{Err: err, Code: code}
+}
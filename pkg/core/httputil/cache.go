@@ -0,0 +1,145 @@
+package httputil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/cache"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/svcstatus"
+)
+
+// CachingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it answers the requests of immutable artifact content, of the metadata of the Service Registry instances
+// and of the artifact types from the cache, and caches the responses of the servers to these requests.
+type CachingRoundTripper struct {
+	Proxied http.RoundTripper
+	Cache   *cache.Cache
+	Logger  logging.Logger
+	// Principal identifies who the requests are sent for, such as the login profile and the user or service account.
+	// Cached responses are only used for the principal they were cached for.
+	Principal string
+	// TTL is how long the metadata of the instances and the artifact types are cached.
+	// They are not cached when it is not positive.
+	TTL time.Duration
+}
+
+// cacheRule describes the requests which are cached
+type cacheRule struct {
+	// path matches the path of the requests, the instance being the first group
+	path *regexp.Regexp
+	// immutable responses never expire
+	immutable bool
+	// registryID tells if the instance is the registry ID of the management API,
+	// instead of the host and base path of the API of the instance
+	registryID bool
+	// cacheable tells if the response can be cached, when it is set
+	cacheable func(body []byte) bool
+}
+
+var cacheRules = []cacheRule{
+	// the content of an artifact version never changes, whether it is referenced by global ID, content ID or hash
+	{
+		path:      regexp.MustCompile(`^(.*)/ids/(?:globalIds|contentIds|contentHashes)/[^/]+(?:/|/references)?$`),
+		immutable: true,
+	},
+	{
+		path: regexp.MustCompile(`^(.*)/admin/artifactTypes$`),
+	},
+	// instances which are not ready yet are fetched again until they are ready
+	{
+		path:       regexp.MustCompile(`/api/serviceregistry_mgmt/v1/registries/([^/]+)$`),
+		registryID: true,
+		cacheable:  registryReady,
+	},
+}
+
+// RoundTrip answers the request from the cache, or sends it and caches the response
+func (c *CachingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	rule, instance := c.match(r)
+	if rule == nil {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	key := principalHash(c.Principal) + " " + r.URL.String()
+	entry, err := c.Cache.Get(instance, key)
+	if err != nil {
+		c.Logger.Debugf("Unable to read the cache: %v", err)
+	}
+	if entry != nil {
+		c.Logger.Debugf("Using the cached response of %v %v", r.Method, redactURL(r.URL))
+		return entry.Response(r), nil
+	}
+
+	resp, err := c.Proxied.RoundTrip(r)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rule.cacheable != nil && !rule.cacheable(body) {
+		return resp, nil
+	}
+
+	entry = &cache.Entry{
+		Instance: instance,
+		Key:      key,
+		Status:   resp.StatusCode,
+		Header:   resp.Header.Clone(),
+		Body:     body,
+		Created:  time.Now(),
+	}
+	entry.Header.Del("Set-Cookie")
+	if !rule.immutable {
+		entry.Expires = entry.Created.Add(c.TTL)
+	}
+	if err = c.Cache.Put(entry); err != nil {
+		c.Logger.Debugf("Unable to cache the response of %v %v: %v", r.Method, redactURL(r.URL), err)
+	}
+
+	return resp, nil
+}
+
+// match returns the rule caching the request, and the instance it is cached for
+func (c *CachingRoundTripper) match(r *http.Request) (*cacheRule, string) {
+	if r.Method != http.MethodGet || r.Header.Get("Range") != "" {
+		return nil, ""
+	}
+
+	for i := range cacheRules {
+		rule := &cacheRules[i]
+		if !rule.immutable && c.TTL <= 0 {
+			continue
+		}
+		groups := rule.path.FindStringSubmatch(r.URL.Path)
+		if groups == nil {
+			continue
+		}
+		if rule.registryID {
+			return rule, groups[1]
+		}
+		return rule, r.URL.Host + groups[1]
+	}
+	return nil, ""
+}
+
+// principalHash returns a hash of the principal, so that the cached entries do not tell who they were cached for
+func principalHash(principal string) string {
+	sum := sha256.Sum256([]byte(principal))
+	return hex.EncodeToString(sum[:16])
+}
+
+// registryReady tells if the Service Registry instance is ready, its status being final until it is deleted
+func registryReady(body []byte) bool {
+	var registry struct {
+		Status string `json:"status"`
+	}
+	return json.Unmarshal(body, &registry) == nil && registry.Status == svcstatus.StatusReady
+}
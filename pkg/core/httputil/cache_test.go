@@ -0,0 +1,106 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/cache"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+)
+
+func TestCachingRoundTripper(t *testing.T) {
+	calls := map[string]int{}
+	status := "provisioning"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/serviceregistry_mgmt/v1/registries/abc":
+			_, _ = io.WriteString(w, `{"id":"abc","status":"`+status+`"}`)
+		case "/apis/registry/v2/ids/globalIds/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = io.WriteString(w, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	logger, err := logging.NewStdLoggerBuilder().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := &CachingRoundTripper{
+		Proxied:   http.DefaultTransport,
+		Cache:     cache.New(t.TempDir(), 0),
+		Logger:    logger,
+		Principal: "profile:\nuser:alice",
+		TTL:       time.Hour,
+	}
+	client := &http.Client{Transport: rt}
+	get := func(path string) string {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	paths := []string{
+		"/apis/registry/v2/ids/globalIds/1",
+		"/apis/registry/v2/ids/contentHashes/abcdef/",
+		"/apis/registry/v2/admin/artifactTypes",
+		"/apis/registry/v2/ids/globalIds/404",
+		"/apis/registry/v2/search/artifacts",
+		"/api/serviceregistry_mgmt/v1/registries/abc",
+	}
+	for i := 0; i < 2; i++ {
+		for _, path := range paths {
+			get(path)
+		}
+	}
+	// the instance is ready from now on
+	status = "ready"
+	for i := 0; i < 2; i++ {
+		if body := get(paths[5]); !strings.Contains(body, "ready") {
+			t.Errorf("instance = %v, want it ready", body)
+		}
+	}
+
+	// the responses cached for another user are not used
+	other := *rt
+	other.Principal = "profile:\nuser:bob"
+	client.Transport = &other
+	get(paths[0])
+	client.Transport = rt
+	get(paths[0])
+
+	for path, want := range map[string]int{
+		paths[0]: 2,
+		paths[1]: 1,
+		paths[2]: 1,
+		paths[3]: 2,
+		paths[4]: 2,
+		paths[5]: 3,
+	} {
+		if calls[path] != want {
+			t.Errorf("%v was sent %v times, want %v", path, calls[path], want)
+		}
+	}
+
+	stats, err := rt.Cache.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := strings.TrimPrefix(srv.URL, "http://") + "/apis/registry/v2"
+	if len(stats) != 2 || stats[0].Instance != instance || stats[0].Entries != 4 || stats[1].Instance != "abc" {
+		t.Errorf("stats = %+v, want the entries of %v and abc", stats, instance)
+	}
+}
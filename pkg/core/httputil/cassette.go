@@ -0,0 +1,218 @@
+package httputil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// RecordEnvName is the environment variable which sets the cassette the HTTP interactions are recorded to
+	RecordEnvName = "APICR_RECORD"
+	// ReplayEnvName is the environment variable which sets the cassette the HTTP interactions are replayed from
+	ReplayEnvName = "APICR_REPLAY"
+)
+
+// Cassette is a recording of HTTP interactions, with their secrets redacted
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+
+	mu sync.Mutex
+}
+
+// Interaction is a request and the response it received
+type Interaction struct {
+	Request  RecordedRequest  `yaml:"request"`
+	Response RecordedResponse `yaml:"response"`
+}
+
+// RecordedRequest is the recording of a request
+type RecordedRequest struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// RecordedResponse is the recording of a response
+type RecordedResponse struct {
+	Status  int         `yaml:"status"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// LoadCassette reads the cassette from the file
+func LoadCassette(path string) (*Cassette, error) {
+	// #nosec G304
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err = yaml.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette \"%v\": %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to the file
+func (c *Cassette) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// record adds the interaction to the cassette and writes it to the file
+func (c *Cassette) record(path string, interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+	return c.Save(path)
+}
+
+// RecordingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it records the HTTP interactions to the cassette at Path, which is written after each of them.
+// The secrets of the headers, the query parameters and the form and JSON bodies are redacted.
+type RecordingRoundTripper struct {
+	Proxied http.RoundTripper
+	Path    string
+	// Cassette is shared by the round trippers of the clients recording to the same Path.
+	// A new cassette is started when it is nil.
+	Cassette *Cassette
+
+	once sync.Once
+}
+
+// RoundTrip sends the request and records it along with its response
+func (c *RecordingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := r.Clone(r.Context())
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Proxied.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: recordRequest(req, reqBody),
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    redactBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+
+	c.once.Do(func() {
+		if c.Cassette == nil {
+			c.Cassette = &Cassette{}
+		}
+	})
+	if err = c.Cassette.record(c.Path, interaction); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to record interaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReplayingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it responds to the requests with the interactions of the cassette, without sending them.
+// A request is matched to the first interaction with the same method and URL which was not replayed yet,
+// preferring the ones with the same body. Requests which match no interaction fail.
+type ReplayingRoundTripper struct {
+	Cassette *Cassette
+
+	mu       sync.Mutex
+	replayed map[int]bool
+}
+
+// RoundTrip responds to the request with the recorded response
+func (c *ReplayingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := r.Clone(r.Context())
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	recorded := recordRequest(req, body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replayed == nil {
+		c.replayed = make(map[int]bool)
+	}
+
+	match := -1
+	for i, interaction := range c.Cassette.Interactions {
+		if c.replayed[i] || interaction.Request.Method != recorded.Method || interaction.Request.URL != recorded.URL {
+			continue
+		}
+		if interaction.Request.Body == recorded.Body {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no interaction of the cassette matches %v %v", recorded.Method, recorded.URL)
+	}
+	c.replayed[match] = true
+
+	response := c.Cassette.Interactions[match].Response
+	header := response.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       r,
+	}, nil
+}
+
+// readBody reads the body, which is replaced with a copy that can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func recordRequest(r *http.Request, body []byte) RecordedRequest {
+	return RecordedRequest{
+		Method:  r.Method,
+		URL:     redactURL(r.URL),
+		Headers: redactHeaders(r.Header),
+		Body:    redactBody(r.Header.Get("Content-Type"), body),
+	}
+}
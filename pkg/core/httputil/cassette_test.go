@@ -0,0 +1,97 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"access_token":"secret-access-token","expires_in":60}`)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, r.Method+" "+r.URL.Path+" "+string(body))
+		}
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+
+	send := func(t *testing.T, client *http.Client) []string {
+		var bodies []string
+		for _, req := range []func() (*http.Request, error){
+			func() (*http.Request, error) {
+				form := url.Values{"grant_type": {"client_credentials"}, "client_secret": {"secret-client-secret"}}
+				req, err := http.NewRequest(http.MethodPost, srv.URL+"/token", strings.NewReader(form.Encode()))
+				if req != nil {
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				}
+				return req, err
+			},
+			func() (*http.Request, error) {
+				req, err := http.NewRequest(http.MethodGet, srv.URL+"/artifacts?access_token=secret-query-token", http.NoBody)
+				if req != nil {
+					req.Header.Set("Authorization", "Bearer secret-bearer-token")
+				}
+				return req, err
+			},
+			func() (*http.Request, error) {
+				return http.NewRequest(http.MethodPut, srv.URL+"/artifacts/a", strings.NewReader("v2"))
+			},
+		} {
+			r, err := req()
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			bodies = append(bodies, string(body))
+		}
+		return bodies
+	}
+
+	recorded := send(t, &http.Client{Transport: &RecordingRoundTripper{Proxied: http.DefaultTransport, Path: path}})
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-") {
+		t.Errorf("the cassette contains secrets:\n%s", data)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cassette.Interactions) != 3 {
+		t.Fatalf("interactions = %v, want 3", len(cassette.Interactions))
+	}
+
+	replaying := &http.Client{Transport: &ReplayingRoundTripper{Cassette: cassette}}
+	replayed := send(t, replaying)
+	for i := range recorded {
+		// the recorded token response is redacted
+		if i > 0 && replayed[i] != recorded[i] {
+			t.Errorf("replayed body %v = %q, want %q", i, replayed[i], recorded[i])
+		}
+	}
+
+	// every interaction is replayed once
+	if _, err = replaying.Get(srv.URL + "/artifacts?access_token=secret-query-token"); err == nil {
+		t.Error("expected an error for a request which matches no interaction")
+	}
+}
@@ -2,44 +2,175 @@
 package httputil
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
 )
 
+const (
+	// LogLevelErrors logs the requests which fail, with a status code >= 400
+	LogLevelErrors = "errors"
+	// LogLevelAll logs all the requests
+	LogLevelAll = "all"
+)
+
+// LogLevels are the valid levels of the LoggingRoundTripper
+var LogLevels = []string{LogLevelErrors, LogLevelAll}
+
+// maxLoggedBodySize is the size up to which the bodies are logged
+const maxLoggedBodySize = 64 << 10
+
 // LoggingRoundTripper implements http.RoundTripper. When set as Transport of http.Client, it executes HTTP requests with logging.
+// The bearer tokens, client secrets and credentials of the requests and responses are redacted.
 type LoggingRoundTripper struct {
 	Proxied http.RoundTripper
 	Logger  logging.Logger
+	// Level selects the logged requests, LogLevelErrors when not set
+	Level string
+	// Curl renders the requests as equivalent curl commands
+	Curl bool
 }
 
-// RoundTrip logs the http request and response in debug mode
-// for all errors, where status code >= 400
+// RoundTrip logs the http request and response in debug mode,
+// along with the time the request took
 func (c LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !c.Logger.DebugEnabled() {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	start := time.Now()
 	resp, err := c.Proxied.RoundTrip(r)
+	elapsed := time.Since(start).Round(time.Millisecond)
 	if err != nil {
+		c.Logger.Debug(fmt.Sprintf("%v %v failed after %v: %v", r.Method, redactURL(r.URL), elapsed, err))
+		c.Logger.Debug(c.dumpRequest(r))
 		return nil, err
 	}
 
-	// only dump the HTTP request and response for errors
-	if resp.StatusCode < http.StatusBadRequest {
+	// by default, only dump the HTTP request and response for errors
+	if resp.StatusCode < http.StatusBadRequest && c.Level != LogLevelAll {
 		return resp, nil
 	}
 
-	requestDump, err := httputil.DumpRequest(r, true)
+	c.Logger.Debug(fmt.Sprintf("%v %v %v (%v)", r.Method, redactURL(r.URL), resp.Status, elapsed))
+	c.Logger.Debug(c.dumpRequest(r))
+
+	responseDump, err := dumpResponse(resp)
 	if err != nil {
 		return nil, err
 	}
+	c.Logger.Debug(responseDump)
 
-	c.Logger.Debug(string(requestDump))
+	return resp, nil
+}
 
-	responseDump, err := httputil.DumpResponse(resp, true)
+// dumpRequest renders the request, redacted, as a curl command or as it is sent.
+// The body is only rendered when it can be read again.
+func (c LoggingRoundTripper) dumpRequest(r *http.Request) string {
+	var body []byte
+	hasBody := r.Body != nil && r.Body != http.NoBody
+	bodyKnown := !hasBody
+	if hasBody && r.GetBody != nil {
+		if rc, err := r.GetBody(); err == nil {
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+			bodyKnown = true
+		}
+	}
+
+	req := r.Clone(r.Context())
+	req.Header = redactHeaders(r.Header)
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	bodyText := loggedBody(redactBody(r.Header.Get("Content-Type"), body), bodyKnown)
+
+	if c.Curl {
+		return curlCommand(req, bodyText, hasBody)
+	}
+
+	req.Body = io.NopCloser(strings.NewReader(bodyText))
+	req.ContentLength = int64(len(bodyText))
+	if u, err := url.Parse(redactURL(r.URL)); err == nil {
+		req.URL = u
+	}
+	requestDump, err := httputil.DumpRequest(req, true)
 	if err != nil {
-		return nil, err
+		return err.Error()
 	}
+	return string(requestDump)
+}
 
-	c.Logger.Debug(string(responseDump))
+// dumpResponse renders the response, redacted. The body of the response is read and restored.
+func dumpResponse(resp *http.Response) (string, error) {
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return "", err
+	}
 
-	return resp, nil
+	logged := *resp
+	logged.Header = redactHeaders(resp.Header)
+	bodyText := loggedBody(redactBody(resp.Header.Get("Content-Type"), body), true)
+	logged.Body = io.NopCloser(strings.NewReader(bodyText))
+	logged.ContentLength = int64(len(bodyText))
+
+	responseDump, err := httputil.DumpResponse(&logged, true)
+	if err != nil {
+		return "", err
+	}
+	return string(responseDump), nil
+}
+
+// loggedBody truncates the body to the size which is logged
+func loggedBody(body string, known bool) string {
+	switch {
+	case !known:
+		return "<body not logged: it cannot be read again>"
+	case !utf8.ValidString(body):
+		return fmt.Sprintf("<binary body of %v bytes>", len(body))
+	case len(body) > maxLoggedBodySize:
+		return fmt.Sprintf("%v... <%v more bytes>", body[:maxLoggedBodySize], len(body)-maxLoggedBodySize)
+	default:
+		return body
+	}
+}
+
+// curlCommand renders the request as an equivalent curl command
+func curlCommand(r *http.Request, body string, hasBody bool) string {
+	var b bytes.Buffer
+	b.WriteString("curl")
+	if r.Method != http.MethodGet || hasBody {
+		b.WriteString(" -X " + r.Method)
+	}
+	b.WriteString(" " + shellQuote(redactURL(r.URL)))
+
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range r.Header[name] {
+			b.WriteString(" \\\n  -H " + shellQuote(name+": "+value))
+		}
+	}
+
+	if hasBody {
+		b.WriteString(" \\\n  --data-raw " + shellQuote(body))
+	}
+	return b.String()
+}
+
+// shellQuote quotes the value for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
@@ -0,0 +1,84 @@
+package httputil
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+)
+
+func TestLoggingRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = io.WriteString(w, `{"access_token":"secret-access-token","name":"orders"}`)
+	}))
+	defer srv.Close()
+
+	send := func(t *testing.T, rt LoggingRoundTripper, path string) string {
+		var out bytes.Buffer
+		logger, err := logging.NewStdLoggerBuilder().Streams(&out, &out).Debug(true).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rt.Proxied = http.DefaultTransport
+		rt.Logger = logger
+
+		form := url.Values{"client_id": {"svc"}, "client_secret": {"secret-client-secret"}}
+		req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer secret-bearer-token")
+
+		resp, err := (&http.Client{Transport: rt}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		// the response is unchanged by the logging
+		if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "secret-access-token") {
+			t.Errorf("body = %q, want the original body", body)
+		}
+
+		log := out.String()
+		if strings.Contains(log, "secret-") {
+			t.Errorf("the log contains secrets:\n%s", log)
+		}
+		return log
+	}
+
+	t.Run("errors are logged by default", func(t *testing.T) {
+		if log := send(t, LoggingRoundTripper{}, "/orders"); log != "" {
+			t.Errorf("log = %q, want no log for a successful request", log)
+		}
+		log := send(t, LoggingRoundTripper{}, "/missing")
+		for _, want := range []string{"POST " + srv.URL + "/missing 404 Not Found (", "Authorization: Bearer REDACTED", "client_secret=REDACTED", `"access_token":"REDACTED"`} {
+			if !strings.Contains(log, want) {
+				t.Errorf("log does not contain %q:\n%s", want, log)
+			}
+		}
+	})
+
+	t.Run("all requests are logged as curl commands", func(t *testing.T) {
+		log := send(t, LoggingRoundTripper{Level: LogLevelAll, Curl: true}, "/orders")
+		for _, want := range []string{
+			"POST " + srv.URL + "/orders 200 OK (",
+			"curl -X POST '" + srv.URL + "/orders'",
+			"-H 'Authorization: Bearer REDACTED'",
+			"--data-raw 'client_id=svc&client_secret=REDACTED'",
+		} {
+			if !strings.Contains(log, want) {
+				t.Errorf("log does not contain %q:\n%s", want, log)
+			}
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the secrets of the logged and recorded HTTP interactions
+const redacted = "REDACTED"
+
+// sensitiveHeaders are the headers whose values are redacted
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// sensitiveFields are the query parameters, form fields and JSON fields whose values are redacted
+var sensitiveFields = map[string]bool{
+	"access_token":     true,
+	"refresh_token":    true,
+	"id_token":         true,
+	"token":            true,
+	"client_secret":    true,
+	"clientsecret":     true,
+	"secret":           true,
+	"client_assertion": true,
+	"assertion":        true,
+	"password":         true,
+	"code":             true,
+	"code_verifier":    true,
+	"device_code":      true,
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.String()
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = redactValues(query).Encode()
+	return redactedURL.String()
+}
+
+func redactHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	header = header.Clone()
+	for _, name := range sensitiveHeaders {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		// the authentication scheme tells how the request was authenticated
+		if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(name, "Authorization") {
+			header.Set(name, scheme+" "+redacted)
+		} else {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+func redactValues(values url.Values) url.Values {
+	for key := range values {
+		if sensitiveFields[strings.ToLower(key)] {
+			values.Set(key, redacted)
+		}
+	}
+	return values
+}
+
+// redactBody redacts the secrets of form and JSON bodies
+func redactBody(contentType string, body []byte) string {
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			return redactValues(values).Encode()
+		}
+	case strings.Contains(contentType, "json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err == nil && redactJSON(value) {
+			if data, err := json.Marshal(value); err == nil {
+				return string(data)
+			}
+		}
+	}
+	return string(body)
+}
+
+// redactJSON redacts the sensitive fields of the JSON value, and reports whether there were any
+func redactJSON(value interface{}) (changed bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				changed = true
+			} else if redactJSON(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactJSON(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,123 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxReplayBodySize is the size up to which request bodies are buffered, so that they can be sent again
+const maxReplayBodySize = 32 << 20
+
+// TokenSource provides the access token of the user to the RefreshingRoundTripper
+type TokenSource interface {
+	// Issued reports whether the access token was issued to the user by the source
+	Issued(accessToken string) bool
+	// AccessToken returns the current access token, refreshing it first when it is about to expire
+	AccessToken(ctx context.Context) (string, error)
+	// RefreshAccessToken refreshes the access token after a server rejected it, unless it was already refreshed
+	RefreshAccessToken(ctx context.Context, rejected string) (string, error)
+}
+
+// RefreshingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it keeps the access token of the requests sent with the tokens of the user valid:
+// the token is refreshed before it expires, and a request rejected with 401 Unauthorized
+// is sent once more with a refreshed token.
+type RefreshingRoundTripper struct {
+	Proxied http.RoundTripper
+	Source  TokenSource
+}
+
+// RoundTrip sends the request with the current access token of the user
+func (c *RefreshingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	accessToken, ok := bearerToken(r)
+	if !ok || c.Source == nil || !c.Source.Issued(accessToken) {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	accessToken, err := c.Source.AccessToken(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	getBody, body, err := replayableBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := withToken(r, accessToken)
+	req.Body = body
+	resp, err := c.Proxied.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || getBody == nil {
+		return resp, err
+	}
+
+	refreshed, err := c.Source.RefreshAccessToken(r.Context(), accessToken)
+	if err != nil || refreshed == accessToken {
+		// the original response is more useful than the error of the refresh
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	req = withToken(r, refreshed)
+	if req.Body, err = getBody(); err != nil {
+		return nil, err
+	}
+	return c.Proxied.RoundTrip(req)
+}
+
+// bearerToken returns the bearer token of the request
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(auth, prefix), true
+}
+
+// withToken returns a copy of the request with the access token, leaving the request of the caller unmodified
+func withToken(r *http.Request, accessToken string) *http.Request {
+	req := r.Clone(r.Context())
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return req
+}
+
+// replayableBody returns a function which returns the body of the request again, along with the body to send first.
+// Bodies which cannot be read again are buffered, unless they are too large to be replayed,
+// in which case the returned function is nil.
+func replayableBody(r *http.Request) (func() (io.ReadCloser, error), io.ReadCloser, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return func() (io.ReadCloser, error) { return http.NoBody, nil }, r.Body, nil
+	}
+
+	if r.GetBody != nil {
+		return r.GetBody, r.Body, nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxReplayBodySize+1))
+	if err != nil {
+		r.Body.Close()
+		return nil, nil, err
+	}
+	if len(buf) > maxReplayBodySize {
+		// the body is sent as it is read, without buffering the rest of it
+		return nil, readCloser{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}, nil
+	}
+	r.Body.Close()
+
+	getBody := func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}
+	body, _ := getBody()
+	return getBody, body, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,112 @@
+package httputil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeTokens is a TokenSource which refreshes "old" to "new"
+type fakeTokens struct {
+	current   string
+	refreshes int
+}
+
+func (f *fakeTokens) Issued(accessToken string) bool {
+	return accessToken == "old" || accessToken == "new"
+}
+
+func (f *fakeTokens) AccessToken(context.Context) (string, error) {
+	return f.current, nil
+}
+
+func (f *fakeTokens) RefreshAccessToken(_ context.Context, rejected string) (string, error) {
+	if f.current == rejected {
+		f.refreshes++
+		f.current = "new"
+	}
+	return f.current, nil
+}
+
+func TestRefreshingRoundTripper(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Header.Get("Authorization")+" "+string(body))
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		current       string
+		auth          string
+		wantStatus    int
+		wantBodies    []string
+		wantRefreshes int
+	}{
+		{
+			name:          "retries once with a refreshed token",
+			current:       "old",
+			auth:          "Bearer old",
+			wantStatus:    http.StatusOK,
+			wantBodies:    []string{"Bearer old payload", "Bearer new payload"},
+			wantRefreshes: 1,
+		},
+		{
+			name:       "uses the current token of the source",
+			current:    "new",
+			auth:       "Bearer old",
+			wantStatus: http.StatusOK,
+			wantBodies: []string{"Bearer new payload"},
+		},
+		{
+			name:       "leaves other tokens alone",
+			current:    "old",
+			auth:       "Bearer other",
+			wantStatus: http.StatusUnauthorized,
+			wantBodies: []string{"Bearer other payload"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies = nil
+			tokens := &fakeTokens{current: tt.current}
+			client := &http.Client{Transport: &RefreshingRoundTripper{
+				Proxied: http.DefaultTransport,
+				Source:  tokens,
+			}}
+
+			// the body cannot be read again, so it must be buffered to be replayed
+			req, err := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("payload")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", tt.auth)
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("status = %v, want %v", res.StatusCode, tt.wantStatus)
+			}
+			if strings.Join(bodies, "|") != strings.Join(tt.wantBodies, "|") {
+				t.Errorf("requests = %q, want %q", bodies, tt.wantBodies)
+			}
+			if tokens.refreshes != tt.wantRefreshes {
+				t.Errorf("refreshes = %v, want %v", tokens.refreshes, tt.wantRefreshes)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.auth {
+				t.Errorf("the request of the caller was modified: %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+)
+
+const (
+	// DefaultRetries is the number of times a request is sent again after a transient failure
+	DefaultRetries = 3
+	// DefaultRetryMaxWait is the longest wait before a retry
+	DefaultRetryMaxWait = 30 * time.Second
+	// DefaultRetryBaseWait is the longest wait before the first retry, which doubles for each later retry
+	DefaultRetryBaseWait = 500 * time.Millisecond
+)
+
+// RetryingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it sends the requests again when they fail with a transient error: a network error,
+// or a 429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable or 504 Gateway Timeout response.
+// Only idempotent requests are retried, along with POST requests when RetryPOST is set.
+// The wait before each retry is random and grows exponentially, unless the response sets it with Retry-After.
+type RetryingRoundTripper struct {
+	Proxied http.RoundTripper
+	Logger  logging.Logger
+	// Retries is the number of times a request is sent again
+	Retries int
+	// MaxWait caps the wait before each retry.
+	// Responses which ask to wait longer with Retry-After are returned instead of retried.
+	MaxWait time.Duration
+	// BaseWait is the longest wait before the first retry, DefaultRetryBaseWait when not set
+	BaseWait time.Duration
+	// RetryPOST allows POST requests to be retried, which may create the same resource twice
+	RetryPOST bool
+}
+
+// RoundTrip sends the request, and sends it again after transient failures
+func (c *RetryingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if c.Retries <= 0 || !c.isRetryable(r) {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	getBody, body, err := replayableBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if getBody == nil {
+		// the body is too large to be sent again
+		req := r.Clone(r.Context())
+		req.Body = body
+		return c.Proxied.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req := r.Clone(r.Context())
+		req.Body = body
+		resp, err := c.Proxied.RoundTrip(req)
+		if attempt == c.Retries || !isTransient(resp, err) {
+			return resp, err
+		}
+
+		wait, ok := c.wait(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if c.Logger != nil {
+			c.Logger.Debug("Retrying", r.Method, r.URL.String(), "in", wait.String(), "after", transientFailure(resp, err))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, r.Context().Err()
+		case <-timer.C:
+		}
+
+		if body, err = getBody(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable reports whether sending the request again has the same effect as sending it once
+func (c *RetryingRoundTripper) isRetryable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return c.RetryPOST
+	default:
+		return false
+	}
+}
+
+// wait returns the wait before the retry following the attempt,
+// and false when the response asks to wait longer than MaxWait
+func (c *RetryingRoundTripper) wait(attempt int, resp *http.Response) (time.Duration, bool) {
+	maxWait := c.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+
+	if resp != nil {
+		if wait, ok := retryAfter(resp); ok {
+			return wait, wait <= maxWait
+		}
+	}
+
+	baseWait := c.BaseWait
+	if baseWait <= 0 {
+		baseWait = DefaultRetryBaseWait
+	}
+	ceiling := maxWait
+	if attempt < 30 && baseWait<<attempt < maxWait {
+		ceiling = baseWait << attempt
+	}
+
+	// the jitter spreads the retries of concurrent requests
+	// #nosec G404
+	return time.Duration(rand.Int63n(int64(ceiling) + 1)), true
+}
+
+// retryAfter returns the wait set by the Retry-After header of the response,
+// either in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isTransient reports whether the request failed with an error which may not happen again
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return isTransientError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientError reports whether the request failed with a network error which may not happen again:
+// a timeout, a connection which was refused, reset or closed early, or a temporary DNS failure.
+// Other errors, such as untrusted certificates, unknown hosts or requests missing from a cassette, fail at once.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func transientFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
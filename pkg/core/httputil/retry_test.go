@@ -0,0 +1,211 @@
+package httputil
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first requests with the status, and then responds with the body of the request.
+// A failure with status 0 drops the connection.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) > failures {
+			_, _ = w.Write(body)
+			return
+		}
+		if status == 0 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetryingRoundTripper(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		body      io.Reader
+		failures  int32
+		status    int
+		header    http.Header
+		retries   int
+		maxWait   time.Duration
+		retryPOST bool
+		// wantStatus is the status of the response returned to the caller
+		wantStatus int
+		wantCalls  int32
+		minElapsed time.Duration
+	}{
+		{
+			name:       "GET is retried until it succeeds",
+			method:     http.MethodGet,
+			failures:   2,
+			status:     http.StatusServiceUnavailable,
+			retries:    3,
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "the last failure is returned once the retries are exhausted",
+			method:     http.MethodGet,
+			failures:   5,
+			status:     http.StatusBadGateway,
+			retries:    2,
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  3,
+		},
+		{
+			name:       "dropped connections are retried",
+			method:     http.MethodDelete,
+			failures:   1,
+			retries:    1,
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "other errors are not retried",
+			method:     http.MethodGet,
+			failures:   1,
+			status:     http.StatusInternalServerError,
+			retries:    3,
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name:       "POST is not retried by default",
+			method:     http.MethodPost,
+			body:       strings.NewReader("artifact"),
+			failures:   1,
+			status:     http.StatusServiceUnavailable,
+			retries:    3,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:   "POST is retried with the same body when allowed",
+			method: http.MethodPost,
+			// a reader the request cannot rewind, so that the body is buffered
+			body:       io.MultiReader(strings.NewReader("artifact")),
+			failures:   2,
+			status:     http.StatusTooManyRequests,
+			retries:    3,
+			retryPOST:  true,
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "Retry-After is honoured",
+			method:     http.MethodGet,
+			failures:   1,
+			status:     http.StatusTooManyRequests,
+			header:     http.Header{"Retry-After": {"1"}},
+			retries:    1,
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+			minElapsed: time.Second,
+		},
+		{
+			name:       "Retry-After longer than the max wait is not retried",
+			method:     http.MethodGet,
+			failures:   1,
+			status:     http.StatusServiceUnavailable,
+			header:     http.Header{"Retry-After": {"120"}},
+			retries:    3,
+			maxWait:    time.Second,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.failures, tt.status, tt.header)
+
+			client := &http.Client{Transport: &RetryingRoundTripper{
+				Proxied:   http.DefaultTransport,
+				Retries:   tt.retries,
+				MaxWait:   tt.maxWait,
+				BaseWait:  time.Millisecond,
+				RetryPOST: tt.retryPOST,
+			}}
+
+			req, err := http.NewRequest(tt.method, srv.URL, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("calls = %v, want %v", got, tt.wantCalls)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.minElapsed)
+			}
+			if tt.body != nil && resp.StatusCode == http.StatusOK {
+				if body, _ := io.ReadAll(resp.Body); string(body) != "artifact" {
+					t.Errorf("body = %q, want %q", body, "artifact")
+				}
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	replay := &ReplayingRoundTripper{Cassette: &Cassette{}}
+	req, _ := http.NewRequest(http.MethodGet, "https://registry.example.com/apis/registry/v2/search/artifacts", nil)
+	_, replayErr := replay.RoundTrip(req)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, want: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: true},
+		{name: "connection closed early", err: &url.Error{Op: "Get", Err: io.EOF}, want: true},
+		{name: "timeout", err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}, want: true},
+		{name: "unknown host", err: &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "registry.invalid", IsNotFound: true}}, want: false},
+		{name: "unknown authority", err: &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, want: false},
+		{name: "cancelled", err: &url.Error{Op: "Get", Err: context.Canceled}, want: false},
+		{name: "cassette miss", err: replayErr, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.want {
+				t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package httputil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/tracing"
+)
+
+// TracingRoundTripper implements http.RoundTripper. When set as Transport of http.Client,
+// it records each request as a span of the trace of the command, when the command is traced.
+// The span lasts until the body of the response is read, and the trace is propagated to the servers
+// with the traceparent header.
+type TracingRoundTripper struct {
+	Proxied http.RoundTripper
+}
+
+// RoundTrip sends the request in a span of the trace of the command
+func (c TracingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	trace := tracing.Active()
+	if trace == nil {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	span := trace.StartSpan(r.Method+" "+r.URL.Path, tracing.SpanKindClient)
+	span.SetAttribute(tracing.AttributeHTTPMethod, r.Method)
+	span.SetAttribute(tracing.AttributeURLFull, redactURL(r.URL))
+	span.SetAttribute(tracing.AttributeServerAddress, r.URL.Hostname())
+	if port, err := strconv.Atoi(r.URL.Port()); err == nil {
+		span.SetAttribute(tracing.AttributeServerPort, port)
+	}
+	if r.ContentLength > 0 {
+		span.SetAttribute(tracing.AttributeHTTPRequestSize, r.ContentLength)
+	}
+
+	req := r.Clone(r.Context())
+	req.Header.Set("traceparent", fmt.Sprintf("00-%v-%v-01", span.TraceID, span.SpanID))
+
+	resp, err := c.Proxied.RoundTrip(req)
+	if err != nil {
+		span.SetAttribute(tracing.AttributeErrorType, fmt.Sprintf("%T", err))
+		span.SetStatus(tracing.StatusError, err.Error())
+		span.Finish()
+		return nil, err
+	}
+
+	span.SetAttribute(tracing.AttributeHTTPStatusCode, resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(tracing.StatusError, resp.Status)
+	}
+	resp.Body = &tracedBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// tracedBody ends the span of the request once the body of the response is read or closed
+type tracedBody struct {
+	io.ReadCloser
+	span *tracing.Span
+	size int64
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *tracedBody) finish() {
+	b.span.SetAttribute(tracing.AttributeHTTPResponseSize, b.size)
+	b.span.Finish()
+}
@@ -0,0 +1,84 @@
+package httputil
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/tracing"
+)
+
+func TestTracingRoundTripper(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = io.WriteString(w, "orders")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: TracingRoundTripper{Proxied: http.DefaultTransport}}
+	get := func(path string) {
+		resp, err := client.Get(srv.URL + path + "?token=secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	// requests are not traced until the command is
+	get("/groups")
+	if traceparent != "" {
+		t.Fatalf("traceparent = %q, the command is not traced", traceparent)
+	}
+
+	trace := tracing.Begin("apicr artifact list")
+	get("/groups")
+	get("/missing")
+	trace.Finish(nil)
+
+	spans := trace.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("got %v spans, want the command and 2 requests", len(spans))
+	}
+	request, missing := spans[1], spans[2]
+	if want := "00-" + trace.Root.TraceID + "-" + missing.SpanID + "-01"; traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+	if request.Name != "GET /groups" || request.ParentSpanID != trace.Root.SpanID {
+		t.Errorf("span %q has parent %q, want GET /groups child of the command", request.Name, request.ParentSpanID)
+	}
+	if request.Status != tracing.StatusUnset || missing.Status != tracing.StatusError {
+		t.Errorf("statuses are %v and %v, want the missing artifact to fail", request.Status, missing.Status)
+	}
+
+	attributes := map[string]interface{}{}
+	for _, attribute := range request.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if attributes[tracing.AttributeHTTPStatusCode] != http.StatusOK || attributes[tracing.AttributeHTTPResponseSize] != int64(6) {
+		t.Errorf("attributes = %v, want status 200 and size 6", attributes)
+	}
+	if port, ok := attributes[tracing.AttributeServerPort].(int); !ok || port != srv.Listener.Addr().(*net.TCPAddr).Port {
+		t.Errorf("server port = %#v, want the port of the server as an int", attributes[tracing.AttributeServerPort])
+	}
+	if url := attributes[tracing.AttributeURLFull].(string); strings.Contains(url, "secret") {
+		t.Errorf("url %q is not redacted", url)
+	}
+
+	var summary strings.Builder
+	if err := trace.WriteSummary(&summary); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"apicr artifact list", "GET " + srv.URL + "/groups", "404", "Time by server:", "2 requests"} {
+		if !strings.Contains(summary.String(), want) {
+			t.Errorf("summary does not contain %q:\n%v", want, summary.String())
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package httputil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportSettings are the TLS and proxy settings of the connections to the APIs and the authentication server
+type TransportSettings struct {
+	Insecure bool
+	// CAFile is a PEM file of certificate authorities trusted in addition to the ones of the system
+	CAFile string
+	// ClientCert and ClientKey are the PEM files of the client certificate used for mutual TLS.
+	// The key defaults to the certificate file, which can hold both.
+	ClientCert string
+	ClientKey  string
+	// ProxyURL is the proxy of the requests to the hosts which do not match NoProxy.
+	// The proxy defaults to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	NoProxy  string
+}
+
+// TrustedCAs returns the certificate authorities of the system along with the ones of CAFile,
+// or nil to use the ones of the system only
+func (s *TransportSettings) TrustedCAs() (*x509.CertPool, error) {
+	if s.CAFile == "" {
+		return nil, nil
+	}
+
+	// #nosec G304
+	data, err := os.ReadFile(s.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA file \"%v\"", s.CAFile)
+	}
+
+	return pool, nil
+}
+
+// ClientCertificates returns the client certificate used for mutual TLS, if any
+func (s *TransportSettings) ClientCertificates() ([]tls.Certificate, error) {
+	if s.ClientCert == "" {
+		if s.ClientKey != "" {
+			return nil, fmt.Errorf("client key \"%v\" set without a client certificate", s.ClientKey)
+		}
+		return nil, nil
+	}
+
+	keyFile := s.ClientKey
+	if keyFile == "" {
+		keyFile = s.ClientCert
+	}
+	cert, err := tls.LoadX509KeyPair(s.ClientCert, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load client certificate: %w", err)
+	}
+
+	return []tls.Certificate{cert}, nil
+}
+
+// Proxy returns the function which selects the proxy of a request
+func (s *TransportSettings) Proxy() (func(*http.Request) (*url.URL, error), error) {
+	if s.ProxyURL == "" {
+		if s.NoProxy == "" {
+			return http.ProxyFromEnvironment, nil
+		}
+		// the hosts excluded by the settings replace the ones of the environment
+		cfg := httpproxy.FromEnvironment()
+		cfg.NoProxy = s.NoProxy
+		return proxyFunc(cfg), nil
+	}
+
+	if u, err := url.Parse(s.ProxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL \"%v\"", s.ProxyURL)
+	}
+
+	return proxyFunc(&httpproxy.Config{
+		HTTPProxy:  s.ProxyURL,
+		HTTPSProxy: s.ProxyURL,
+		NoProxy:    s.NoProxy,
+	}), nil
+}
+
+// Resolve checks that the certificates can be loaded and that the proxy URL is valid.
+// The paths of the files are made absolute, so that the settings can be used from any directory.
+func (s *TransportSettings) Resolve() error {
+	for _, path := range []*string{&s.CAFile, &s.ClientCert, &s.ClientKey} {
+		if *path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			return err
+		}
+		*path = abs
+	}
+
+	_, err := s.Transport()
+	return err
+}
+
+// Transport creates a transport with the settings
+func (s *TransportSettings) Transport() (*http.Transport, error) {
+	trustedCAs, err := s.TrustedCAs()
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := s.ClientCertificates()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := s.Proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec 402
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: s.Insecure,
+			RootCAs:            trustedCAs,
+			Certificates:       certificates,
+		},
+		Proxy: proxy,
+	}, nil
+}
+
+func proxyFunc(cfg *httpproxy.Config) func(*http.Request) (*url.URL, error) {
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
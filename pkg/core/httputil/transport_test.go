@@ -0,0 +1,91 @@
+package httputil
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransportSettings(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("the system CAs reject the server", func(t *testing.T) {
+		transport, err := (&TransportSettings{NoProxy: "*"}).Transport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = (&http.Client{Transport: transport}).Get(srv.URL); err == nil {
+			t.Fatal("expected a certificate error")
+		}
+	})
+
+	t.Run("the CA file is trusted", func(t *testing.T) {
+		transport, err := (&TransportSettings{CAFile: caFile, NoProxy: "*"}).Transport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("status = %v, want %v", resp.StatusCode, http.StatusNoContent)
+		}
+	})
+
+	t.Run("files without certificates are rejected", func(t *testing.T) {
+		settings := &TransportSettings{CAFile: filepath.Join(dir, "missing.pem")}
+		if err := settings.Resolve(); err == nil {
+			t.Error("expected an error for a missing CA file")
+		}
+		settings = &TransportSettings{ClientKey: caFile}
+		if err := settings.Resolve(); err == nil {
+			t.Error("expected an error for a client key without a certificate")
+		}
+	})
+
+	t.Run("hosts matching no_proxy skip the proxy", func(t *testing.T) {
+		proxy, err := (&TransportSettings{ProxyURL: "http://proxy.corp:3128", NoProxy: "internal.corp,10.0.0.0/8"}).Proxy()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for target, want := range map[string]string{
+			"https://registry.example.com/apis": "http://proxy.corp:3128",
+			"https://api.internal.corp/apis":    "",
+			"https://10.1.2.3/apis":             "",
+		} {
+			req, _ := http.NewRequest(http.MethodGet, target, http.NoBody)
+			u, err := proxy(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if u != nil {
+				got = u.String()
+			}
+			if got != want {
+				t.Errorf("proxy of %v = %q, want %q", target, got, want)
+			}
+		}
+	})
+
+	t.Run("invalid proxy URLs are rejected", func(t *testing.T) {
+		if _, err := (&TransportSettings{ProxyURL: "proxy.corp"}).Proxy(); err == nil {
+			t.Error("expected an error for a proxy URL without a scheme")
+		}
+	})
+}
@@ -34,7 +34,18 @@ func JSON(stream io.Writer, body []byte) error {
 	data := ordered.NewOrderedMap()
 	err := json.Unmarshal(body, data)
 	if err != nil {
-		return dumpBytes(stream, body)
+		// documents which are not objects, such as arrays, are indented as they are
+		if !json.Valid(body) {
+			return dumpBytes(stream, body)
+		}
+		if haveJQ() {
+			return dumpJQ(stream, body)
+		}
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, body, "", cmdutil.DefaultJSONIndent); err != nil {
+			return err
+		}
+		return dumpBytes(stream, buf.Bytes())
 	}
 	if haveJQ() {
 		return dumpJQ(stream, body)
@@ -0,0 +1,71 @@
+// Package lockedfile writes files which are shared between concurrent processes.
+// Writers hold an exclusive lock on a lock file next to the file,
+// and replace the file atomically so that readers never see a partial write.
+package lockedfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const lockSuffix = ".lock"
+
+// Lock acquires an exclusive lock for path, waiting until other processes release it.
+// The lock is held on a separate lock file, which is left in place once released.
+// The returned function releases the lock.
+func Lock(path string) (unlock func() error, err error) {
+	// #nosec G304
+	f, err := os.OpenFile(path+lockSuffix, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open lock file: %w", err)
+	}
+
+	if err = lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to lock %v: %w", path, err)
+	}
+
+	return func() error {
+		err := unlockFile(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// WriteFile writes data to a temporary file in the directory of path and renames it to path,
+// so that the file is either fully written or left untouched
+func WriteFile(path string, data []byte, perm os.FileMode) (err error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
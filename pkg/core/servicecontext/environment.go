@@ -0,0 +1,162 @@
+package servicecontext
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+)
+
+// EnvironmentConfig is a config which applies the connection endpoints and tokens
+// of the active context environment on top of the config file.
+// Tokens saved while a context with a token reference is active are stored under that reference,
+// so that every environment keeps its own login.
+type EnvironmentConfig struct {
+	Config  config.IConfig
+	Context IContext
+}
+
+// NewEnvironmentConfig creates a config driven by the active context of svcContext
+func NewEnvironmentConfig(cfg config.IConfig, svcContext IContext) config.IConfig {
+	return &EnvironmentConfig{
+		Config:  cfg,
+		Context: svcContext,
+	}
+}
+
+// ActiveEnvironment returns the active context when it has its own environment, or nil
+func ActiveEnvironment(svcContext IContext) (*Context, *ServiceConfig) {
+	ctx, err := svcContext.Load()
+	if err != nil {
+		return nil, nil
+	}
+
+	svcConfig, ok := ctx.Contexts[ctx.ActiveName()]
+	if !ok || !svcConfig.HasEnvironment() {
+		return nil, nil
+	}
+
+	return ctx, &svcConfig
+}
+
+// Load loads the config file and applies the environment of the active context
+func (e *EnvironmentConfig) Load() (*config.Config, error) {
+	cfg, err := e.Config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, env := ActiveEnvironment(e.Context); env != nil {
+		applyEnvironment(cfg, env)
+	}
+
+	return cfg, nil
+}
+
+// Save saves the config file. The tokens and endpoints which belong to the environment
+// of the active context are saved to its token reference and to the context instead.
+func (e *EnvironmentConfig) Save(cfg *config.Config) error {
+	return e.Update(func(current *config.Config) error {
+		*current = *cfg
+		return nil
+	})
+}
+
+// Update applies update to the config of the active context environment and saves it
+// like Save, while holding the lock of the config file
+func (e *EnvironmentConfig) Update(update func(cfg *config.Config) error) error {
+	ctx, env := ActiveEnvironment(e.Context)
+	if env == nil {
+		return e.Config.Update(update)
+	}
+
+	var changed bool
+	err := e.Config.Update(func(stored *config.Config) error {
+		cfg := *stored
+		applyEnvironment(&cfg, env)
+		if err := update(&cfg); err != nil {
+			return err
+		}
+
+		saved := cfg
+
+		if env.TokenRef != "" {
+			saved.Tokens = make(map[string]config.TokenSet, len(stored.Tokens)+1)
+			for ref, tokens := range stored.Tokens {
+				saved.Tokens[ref] = tokens
+			}
+			saved.Tokens[env.TokenRef] = config.TokenSet{
+				AccessToken:  cfg.AccessToken,
+				RefreshToken: cfg.RefreshToken,
+				ClientSecret: cfg.ClientSecret,
+			}
+			saved.AccessToken = stored.AccessToken
+			saved.RefreshToken = stored.RefreshToken
+			saved.ClientSecret = stored.ClientSecret
+		}
+
+		changed = updateEnvironment(&env.APIURL, cfg.APIUrl, &saved.APIUrl, stored.APIUrl)
+		changed = updateEnvironment(&env.AuthURL, cfg.AuthURL, &saved.AuthURL, stored.AuthURL) || changed
+		changed = updateEnvironment(&env.ClientID, cfg.ClientID, &saved.ClientID, stored.ClientID) || changed
+
+		*stored = saved
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+
+	name := ctx.ActiveName()
+	return e.Context.Update(func(ctx *Context) error {
+		svcConfig, ok := ctx.Contexts[name]
+		if !ok {
+			return nil
+		}
+		svcConfig.APIURL = env.APIURL
+		svcConfig.AuthURL = env.AuthURL
+		svcConfig.ClientID = env.ClientID
+		ctx.Contexts[name] = svcConfig
+		return nil
+	})
+}
+
+// Remove removes the config file
+func (e *EnvironmentConfig) Remove() error {
+	return e.Config.Remove()
+}
+
+// Location returns the location of the config file
+func (e *EnvironmentConfig) Location() (string, error) {
+	return e.Config.Location()
+}
+
+func applyEnvironment(cfg *config.Config, env *ServiceConfig) {
+	if env.APIURL != "" {
+		cfg.APIUrl = env.APIURL
+	}
+	if env.AuthURL != "" {
+		cfg.AuthURL = env.AuthURL
+	}
+	if env.ClientID != "" {
+		cfg.ClientID = env.ClientID
+	}
+	if env.TokenRef != "" {
+		tokens := cfg.Tokens[env.TokenRef]
+		cfg.AccessToken = tokens.AccessToken
+		cfg.RefreshToken = tokens.RefreshToken
+		cfg.ClientSecret = tokens.ClientSecret
+	}
+}
+
+// updateEnvironment saves a value which the environment overrides to the environment,
+// keeping the stored value of the config file. It reports whether the environment changed.
+func updateEnvironment(envValue *string, value string, savedValue *string, storedValue string) bool {
+	if *envValue == "" {
+		return false
+	}
+
+	*savedValue = storedValue
+	if *envValue == value {
+		return false
+	}
+
+	*envValue = value
+	return true
+}
@@ -0,0 +1,103 @@
+package servicecontext
+
+import (
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+)
+
+// memoryContext is an IContext stored in memory
+type memoryContext struct {
+	ctx *Context
+}
+
+func (m *memoryContext) Load() (*Context, error) {
+	ctx := *m.ctx
+	ctx.Contexts = make(map[string]ServiceConfig, len(m.ctx.Contexts))
+	for name, svcConfig := range m.ctx.Contexts {
+		ctx.Contexts[name] = svcConfig
+	}
+	return &ctx, nil
+}
+
+func (m *memoryContext) Save(ctx *Context) error {
+	m.ctx = ctx
+	return nil
+}
+
+func (m *memoryContext) Update(update func(*Context) error) error {
+	ctx, _ := m.Load()
+	if err := update(ctx); err != nil {
+		return err
+	}
+	return m.Save(ctx)
+}
+
+func (m *memoryContext) Remove() error             { return nil }
+func (m *memoryContext) Location() (string, error) { return "", nil }
+
+func TestEnvironmentConfig(t *testing.T) {
+	stored := &config.Config{
+		APIUrl:      "https://api.prod",
+		ClientID:    "prod-client",
+		AccessToken: "prod-token",
+		Tokens: map[string]config.TokenSet{
+			"staging": {AccessToken: "staging-token"},
+		},
+	}
+	cfgFile := &config.IConfigMock{
+		LoadFunc: func() (*config.Config, error) {
+			cfg := *stored
+			return &cfg, nil
+		},
+		UpdateFunc: func(update func(*config.Config) error) error {
+			cfg := *stored
+			if err := update(&cfg); err != nil {
+				return err
+			}
+			stored = &cfg
+			return nil
+		},
+	}
+	ctxFile := &memoryContext{ctx: &Context{
+		CurrentContext: "staging",
+		Contexts: map[string]ServiceConfig{
+			"prod":    {},
+			"staging": {APIURL: "https://api.stage", TokenRef: "staging"},
+		},
+	}}
+
+	envConfig := NewEnvironmentConfig(cfgFile, ctxFile)
+
+	cfg, err := envConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIUrl != "https://api.stage" || cfg.ClientID != "prod-client" || cfg.AccessToken != "staging-token" {
+		t.Errorf("Load() = %v %v %v, want the staging environment", cfg.APIUrl, cfg.ClientID, cfg.AccessToken)
+	}
+
+	cfg.AccessToken = "new-staging-token"
+	cfg.APIUrl = "https://api.stage2"
+	if err = envConfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if stored.AccessToken != "prod-token" || stored.APIUrl != "https://api.prod" {
+		t.Errorf("Save() overwrote the global config: %v %v", stored.AccessToken, stored.APIUrl)
+	}
+	if got := stored.Tokens["staging"].AccessToken; got != "new-staging-token" {
+		t.Errorf("Save() staging token = %v, want new-staging-token", got)
+	}
+	if got := ctxFile.ctx.Contexts["staging"].APIURL; got != "https://api.stage2" {
+		t.Errorf("Save() staging API URL = %v, want https://api.stage2", got)
+	}
+
+	ctxFile.ctx.CurrentContext = "prod"
+	cfg, err = envConfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIUrl != "https://api.prod" || cfg.AccessToken != "prod-token" {
+		t.Errorf("Load() = %v %v, want the global config", cfg.APIUrl, cfg.AccessToken)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/lockedfile"
 )
 
 // NewFile creates a new context type
@@ -23,14 +25,26 @@ const ContextEnvName = "RHOAS_CONTEXT"
 
 // Load loads the profiles from the context file. If the context file doesn't exist
 // it will return an empty context object.
+// Unless the location of the context file is set with the RHOAS_CONTEXT environment variable,
+// the project context file of the working directory is loaded along with it.
 func (c *File) Load() (*Context, error) {
 	file, err := c.Location()
 	if err != nil {
 		return nil, err
 	}
 
+	// a project context file which cannot be loaded only fails the commands which use the current context
+	var project *ProjectConfig
+	var projectErr error
+	if !HasCustomLocation() {
+		project, projectErr = findWorkingDirProject()
+	}
+
 	_, err = os.Stat(file)
 	if os.IsNotExist(err) {
+		if project != nil || projectErr != nil {
+			return &Context{Project: project, ProjectError: projectErr}, nil
+		}
 		return nil, err
 	}
 	if err != nil {
@@ -46,30 +60,85 @@ func (c *File) Load() (*Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, "unable to parse contexts", err)
 	}
+	ctx.Project = project
+	ctx.ProjectError = projectErr
 	return &ctx, nil
 }
 
+func findWorkingDirProject() (*ProjectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, nil
+	}
+	return FindProjectConfig(wd)
+}
+
 // Save saves the given profiles to the context file.
+// The file is replaced atomically while holding the lock of the context file.
 func (c *File) Save(cfg *Context) error {
 	file, err := c.Location()
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err = c.ensureDir(file); err != nil {
+		return err
+	}
+	unlock, err := lockedfile.Lock(file)
 	if err != nil {
-		return fmt.Errorf("%v: %w", "unable to marshal context", err)
+		return err
+	}
+	defer unlock() // nolint:errcheck
+	return c.write(file, cfg)
+}
+
+// Update loads the profiles, applies update to them and saves the result
+// while holding the lock of the context file, so that the changes of concurrent
+// processes are not lost. If the context file doesn't exist, update is applied to an empty context.
+func (c *File) Update(update func(ctx *Context) error) error {
+	file, err := c.Location()
+	if err != nil {
+		return err
+	}
+	if err = c.ensureDir(file); err != nil {
+		return err
+	}
+	unlock, err := lockedfile.Lock(file)
+	if err != nil {
+		return err
+	}
+	defer unlock() // nolint:errcheck
+
+	ctx, err := c.Load()
+	if os.IsNotExist(err) {
+		ctx, err = &Context{}, nil
 	}
-	rhoasCfgDir, err := DefaultDir()
 	if err != nil {
 		return err
 	}
-	if _, err = os.Stat(rhoasCfgDir); os.IsNotExist(err) {
-		err = os.Mkdir(rhoasCfgDir, 0o700)
+	if err = update(ctx); err != nil {
+		return err
+	}
+	return c.write(file, ctx)
+}
+
+// ensureDir creates the parent directory of the file
+func (c *File) ensureDir(file string) error {
+	dir := filepath.Dir(file)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		err = os.Mkdir(dir, 0o700)
 		if err != nil {
 			return err
 		}
 	}
-	err = os.WriteFile(file, data, 0o600)
+	return nil
+}
+
+func (c *File) write(file string, cfg *Context) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%v: %w", "unable to marshal context", err)
+	}
+	err = lockedfile.WriteFile(file, data, 0o600)
 	if err != nil {
 		return fmt.Errorf(errorFormat, "unable to save context", err)
 	}
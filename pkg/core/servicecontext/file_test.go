@@ -0,0 +1,95 @@
+package servicecontext
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileUpdateConcurrent(t *testing.T) {
+	t.Setenv(ContextEnvName, filepath.Join(t.TempDir(), "contexts.json"))
+
+	ctxFile := &File{}
+
+	const writers = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("context-%d", i)
+			errs <- ctxFile.Update(func(ctx *Context) error {
+				if ctx.Contexts == nil {
+					ctx.Contexts = make(map[string]ServiceConfig)
+				}
+				ctx.Contexts[name] = ServiceConfig{ServiceRegistryID: name}
+				ctx.CurrentContext = name
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, err := ctxFile.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.Contexts) != writers {
+		t.Errorf("Update() lost updates: %v contexts, want %v", len(ctx.Contexts), writers)
+	}
+	if _, ok := ctx.Contexts[ctx.CurrentContext]; !ok {
+		t.Errorf("current context %q does not exist", ctx.CurrentContext)
+	}
+}
+
+func TestFileLoadInvalidProject(t *testing.T) {
+	t.Setenv(ContextEnvName, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, ProjectYAMLFileName), "serviceregistryID: [\n")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd) // nolint:errcheck
+
+	for _, contexts := range []string{"", `{"contexts":{"dev":{"serviceregistryID":"dev-registry"}},"current_context":"dev"}`} {
+		if contexts != "" {
+			location, err := (&File{}).Location()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = os.MkdirAll(filepath.Dir(location), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, location, contexts)
+		}
+
+		ctx, err := (&File{}).Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v, want the invalid project context file to be reported with the contexts", err)
+		}
+		if ctx.Project != nil || ctx.ProjectError == nil {
+			t.Errorf("Load() project = %v, error %v, want the project context file error", ctx.Project, ctx.ProjectError)
+		}
+		if contexts != "" && ctx.CurrentContext != "dev" {
+			t.Errorf("Load() current context = %q, want the contexts to be loaded", ctx.CurrentContext)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package servicecontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ProjectDirName is the name of the project directory containing the project context file
+	ProjectDirName = ".apicr"
+	// ProjectJSONFileName is the name of the project context file in the project directory
+	ProjectJSONFileName = "context.json"
+	// ProjectYAMLFileName is the name of the project context file in YAML format
+	ProjectYAMLFileName = ".apicr.yaml"
+)
+
+// ProjectConfig is a project-local context, which applies to the commands run
+// from the directory of the project file and its subdirectories
+type ProjectConfig struct {
+	// Context is the name of a context from the contexts file to use as a base
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// ServiceRegistryID pins the Service Registry instance of the project
+	ServiceRegistryID string `json:"serviceregistryID,omitempty" yaml:"serviceregistryID,omitempty"`
+	// Group is the default artifact group of the project
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Output is the default output format of the project
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+
+	// Path is the location of the project file
+	Path string `json:"-" yaml:"-"`
+}
+
+// FindProjectConfig looks for a project file in dir and its parent directories.
+// In every directory, ".apicr/context.json" takes precedence over ".apicr.yaml".
+// It returns nil if no project file is found.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, path := range []string{
+			filepath.Join(dir, ProjectDirName, ProjectJSONFileName),
+			filepath.Join(dir, ProjectYAMLFileName),
+		} {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			return loadProjectConfig(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func loadProjectConfig(path string) (*ProjectConfig, error) {
+	// #nosec G304
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, "unable to read project context file", err)
+	}
+
+	var project ProjectConfig
+	if filepath.Ext(path) == ".json" {
+		// unknown keys are reported like in the YAML file
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&project)
+	} else {
+		err = yaml.UnmarshalStrict(data, &project)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse project context file %v: %w", path, err)
+	}
+
+	project.Path = path
+	return &project, nil
+}
@@ -0,0 +1,89 @@
+package servicecontext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "app", "src")
+	if err := os.MkdirAll(filepath.Join(root, "app", ProjectDirName), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(nested, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(root, ProjectYAMLFileName), "serviceregistryID: root-registry\ngroup: root-group\n")
+	writeFile(t, filepath.Join(root, "app", ProjectYAMLFileName), "serviceregistryID: yaml-registry\n")
+	writeFile(t, filepath.Join(root, "app", ProjectDirName, ProjectJSONFileName), `{"serviceregistryID":"app-registry","output":"yaml"}`)
+
+	tests := []struct {
+		name         string
+		dir          string
+		wantRegistry string
+		wantPath     string
+	}{
+		{
+			name:         "Should prefer the JSON file of the closest directory",
+			dir:          nested,
+			wantRegistry: "app-registry",
+			wantPath:     filepath.Join(root, "app", ProjectDirName, ProjectJSONFileName),
+		},
+		{
+			name:         "Should load the YAML file",
+			dir:          root,
+			wantRegistry: "root-registry",
+			wantPath:     filepath.Join(root, ProjectYAMLFileName),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := FindProjectConfig(tt.dir)
+			if err != nil {
+				t.Fatalf("FindProjectConfig() error = %v", err)
+			}
+			if project == nil {
+				t.Fatal("FindProjectConfig() = nil, want a project")
+			}
+			if project.ServiceRegistryID != tt.wantRegistry {
+				t.Errorf("FindProjectConfig() registry = %v, want %v", project.ServiceRegistryID, tt.wantRegistry)
+			}
+			if project.Path != tt.wantPath {
+				t.Errorf("FindProjectConfig() path = %v, want %v", project.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestFindProjectConfigUnknownKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+	}{
+		{name: "JSON", path: filepath.Join(ProjectDirName, ProjectJSONFileName), content: `{"serviceregistry_id":"registry"}`},
+		{name: "YAML", path: ProjectYAMLFileName, content: "serviceregistry_id: registry\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, ProjectDirName), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, filepath.Join(dir, tt.path), tt.content)
+
+			if _, err := FindProjectConfig(dir); err == nil {
+				t.Error("FindProjectConfig() should fail on a misspelled key")
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+package servicecontext
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+)
+
+// TransportSettings returns the TLS and proxy settings of the config file,
+// with the ones set in the context taking precedence
+func (c *ServiceConfig) TransportSettings(cfg *config.Config) httputil.TransportSettings {
+	settings := httputil.TransportSettings{
+		Insecure:   cfg.Insecure,
+		CAFile:     cfg.CAFile,
+		ClientCert: cfg.ClientCert,
+		ClientKey:  cfg.ClientKey,
+		ProxyURL:   cfg.ProxyURL,
+		NoProxy:    cfg.NoProxy,
+	}
+	if c == nil {
+		return settings
+	}
+
+	if c.CAFile != "" {
+		settings.CAFile = c.CAFile
+	}
+	// the certificate and its key go together
+	if c.ClientCert != "" {
+		settings.ClientCert = c.ClientCert
+		settings.ClientKey = c.ClientKey
+	}
+	if c.ProxyURL != "" {
+		settings.ProxyURL = c.ProxyURL
+	}
+	if c.NoProxy != "" {
+		settings.NoProxy = c.NoProxy
+	}
+
+	return settings
+}
@@ -4,20 +4,91 @@ package servicecontext
 type Context struct {
 	Contexts       map[string]ServiceConfig `json:"contexts,omitempty"`
 	CurrentContext string                   `json:"current_context"`
+
+	// Project is the project-local context found in the working directory, if any.
+	// It is never saved to the contexts file.
+	Project *ProjectConfig `json:"-"`
+	// ProjectError is set when the project context file of the working directory cannot be loaded.
+	// The commands which use the current context fail with it, as the project may select another instance.
+	ProjectError error `json:"-"`
 }
 
 // ServiceConfig is a map of identifiers for the application services
 type ServiceConfig struct {
-	KafkaID           string `json:"kafkaID"`
-	ServiceRegistryID string `json:"serviceregistryID"`
-	NamespaceID       string `json:"namespaceID"`
-	ConnectorID       string `json:"connectorID"`
+	KafkaID           string `json:"kafkaID" yaml:"kafkaID"`
+	ServiceRegistryID string `json:"serviceregistryID" yaml:"serviceregistryID"`
+	NamespaceID       string `json:"namespaceID" yaml:"namespaceID"`
+	ConnectorID       string `json:"connectorID" yaml:"connectorID"`
+
+	// The connection endpoints of the context environment.
+	// When set, they take precedence over the ones of the config file.
+	APIURL   string `json:"api_url,omitempty" yaml:"api_url,omitempty"`
+	AuthURL  string `json:"auth_url,omitempty" yaml:"auth_url,omitempty"`
+	ClientID string `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	// TokenRef is the name the tokens of the context environment are stored under in the config file
+	TokenRef string `json:"token_ref,omitempty" yaml:"token_ref,omitempty"`
+
+	// RegistryURL is the URL of a standalone Apicurio Registry.
+	// When set, the registry is used directly instead of looking up the Service Registry instance with the management API.
+	RegistryURL string `json:"registry_url,omitempty" yaml:"registry_url,omitempty"`
+
+	// Auth selects how the requests of the context are authenticated, instead of the login of the user
+	Auth *AuthConfig `json:"auth,omitempty" yaml:"auth,omitempty"`
+
+	// The TLS and proxy settings of the context.
+	// When set, they take precedence over the ones of the config file.
+	CAFile     string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	ClientCert string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	ProxyURL   string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+	NoProxy    string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+}
+
+// AuthConfig selects the authentication provider of a context.
+// Secrets are never stored in the contexts file, so that contexts can be shared:
+// they are read from files, or from environment variables when no file is set.
+type AuthConfig struct {
+	// Type is the name of the authentication provider: none, basic, bearer or oidc
+	Type string `json:"type" yaml:"type"`
+
+	// Username and PasswordFile are the credentials of the basic provider
+	Username     string `json:"username,omitempty" yaml:"username,omitempty"`
+	PasswordFile string `json:"password_file,omitempty" yaml:"password_file,omitempty"`
+
+	// TokenFile is the file of the static token of the bearer provider
+	TokenFile string `json:"token_file,omitempty" yaml:"token_file,omitempty"`
+
+	// IssuerURL, ClientID, ClientSecretFile and Scopes are the client credentials of the oidc provider
+	IssuerURL        string   `json:"issuer_url,omitempty" yaml:"issuer_url,omitempty"`
+	ClientID         string   `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecretFile string   `json:"client_secret_file,omitempty" yaml:"client_secret_file,omitempty"`
+	Scopes           []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// IsStandalone reports whether the context connects directly to a standalone registry
+func (c *ServiceConfig) IsStandalone() bool {
+	return c.RegistryURL != ""
+}
+
+// HasEnvironment reports whether the context has its own connection endpoints or tokens
+func (c *ServiceConfig) HasEnvironment() bool {
+	return c.APIURL != "" || c.AuthURL != "" || c.ClientID != "" || c.TokenRef != ""
+}
+
+// ActiveName returns the name of the context used by the commands,
+// which is the context of the project when it names one, or the current context
+func (c *Context) ActiveName() string {
+	if c.Project != nil && c.Project.Context != "" {
+		return c.Project.Context
+	}
+	return c.CurrentContext
 }
 
 // IContext is an interface which describes functions for context file
 type IContext interface {
 	Load() (*Context, error)
 	Save(*Context) error
+	Update(func(*Context) error) error
 	Remove() error
 	Location() (string, error)
 }
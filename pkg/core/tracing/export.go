@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EndpointEnvName and TracesEndpointEnvName are the environment variables of OpenTelemetry
+	// which set the OTLP/HTTP endpoint the traces are exported to
+	EndpointEnvName       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnvName = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	// tracesPath is the path of the traces on an OTLP/HTTP endpoint
+	tracesPath = "/v1/traces"
+)
+
+// Resource describes the program which sends the traces
+type Resource struct {
+	ServiceName    string
+	ServiceVersion string
+}
+
+// MarshalOTLP encodes the trace with the OTLP/JSON encoding
+func (t *Trace) MarshalOTLP(resource Resource) ([]byte, error) {
+	spans := t.Spans()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: int(span.Status), Message: span.StatusMessage},
+		})
+	}
+
+	return json.Marshal(otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: otlpAttributes([]Attribute{
+				{Key: "service.name", Value: resource.ServiceName},
+				{Key: "service.version", Value: resource.ServiceVersion},
+			})},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/apicurio/apicurio-cli/pkg/core/tracing", Version: resource.ServiceVersion},
+				Spans: otlpSpans,
+			}},
+		}},
+	})
+}
+
+// ExportFile writes the trace to the file with the OTLP/JSON encoding, which the otlpjsonfile receiver of a collector reads.
+// Each trace is appended to the file on its own line.
+func (t *Trace) ExportFile(path string, resource Resource) error {
+	data, err := t.MarshalOTLP(resource)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to export trace: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("unable to export trace: %w", err)
+	}
+	return nil
+}
+
+// ExportOTLP sends the trace to the OTLP/HTTP endpoint of a collector with the JSON encoding.
+// The path of the traces is added to endpoints without a path.
+func (t *Trace) ExportOTLP(ctx context.Context, client *http.Client, endpoint string, resource Resource) error {
+	data, err := t.MarshalOTLP(resource)
+	if err != nil {
+		return err
+	}
+
+	url := TracesURL(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to export trace: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to export trace: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unable to export trace to %v: %v", url, resp.Status)
+	}
+	return nil
+}
+
+// TracesURL returns the URL the traces are sent to on the endpoint
+func TracesURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if i := strings.Index(endpoint, "://"); i >= 0 && !strings.Contains(endpoint[i+3:], "/") {
+		return endpoint + tracesPath
+	}
+	return endpoint
+}
+
+// EndpointFromEnvironment returns the OTLP/HTTP endpoint set with the environment variables of OpenTelemetry
+func EndpointFromEnvironment() string {
+	if endpoint := os.Getenv(TracesEndpointEnvName); endpoint != "" {
+		return endpoint
+	}
+	if endpoint := os.Getenv(EndpointEnvName); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/") + tracesPath
+	}
+	return ""
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+func otlpAttributes(attributes []Attribute) []otlpAttribute {
+	otlp := make([]otlpAttribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		var value otlpValue
+		switch v := attribute.Value.(type) {
+		case string:
+			value.StringValue = &v
+		case int:
+			s := strconv.Itoa(v)
+			value.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			value.IntValue = &s
+		case float64:
+			value.DoubleValue = &v
+		case bool:
+			value.BoolValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		otlp = append(otlp, otlpAttribute{Key: attribute.Key, Value: value})
+	}
+	return otlp
+}
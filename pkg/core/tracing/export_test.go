@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	trace := Begin("apicr artifact list")
+	span := trace.StartSpan("GET /groups", SpanKindClient)
+	span.SetAttribute(AttributeHTTPStatusCode, 200)
+	trace.Finish(errors.New("unauthorized"))
+
+	var received otlpTraces
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	resource := Resource{ServiceName: "apicr", ServiceVersion: "dev"}
+	if err := trace.ExportOTLP(context.Background(), srv.Client(), srv.URL, resource); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("got %v spans, want 2", len(spans))
+	}
+	if spans[0].Status.Code != int(StatusError) || spans[0].Status.Message != "unauthorized" {
+		t.Errorf("command status = %+v, want the error", spans[0].Status)
+	}
+	if spans[1].ParentSpanID != spans[0].SpanID || *spans[1].Attributes[0].Value.IntValue != "200" {
+		t.Errorf("request span = %+v, want a child of the command with its status", spans[1])
+	}
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	for i := 0; i < 2; i++ {
+		if err := trace.ExportFile(path, resource); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 2 {
+		t.Errorf("file has %v lines, want a trace per line", lines)
+	}
+}
+
+func TestTracesURL(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"http://localhost:4318":              "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":             "http://localhost:4318/v1/traces",
+		"https://collector/custom/v1/traces": "https://collector/custom/v1/traces",
+	} {
+		if got := TracesURL(endpoint); got != want {
+			t.Errorf("TracesURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
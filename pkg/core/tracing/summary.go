@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// The attributes of the spans of the HTTP requests, as named by the semantic conventions of OpenTelemetry
+const (
+	AttributeHTTPMethod       = "http.request.method"
+	AttributeHTTPStatusCode   = "http.response.status_code"
+	AttributeHTTPRequestSize  = "http.request.body.size"
+	AttributeHTTPResponseSize = "http.response.body.size"
+	AttributeServerAddress    = "server.address"
+	AttributeServerPort       = "server.port"
+	AttributeURLFull          = "url.full"
+	AttributeErrorType        = "error.type"
+)
+
+// WriteSummary writes the time taken by the command and by each of its requests,
+// along with the time spent waiting for each server
+func (t *Trace) WriteSummary(w io.Writer) error {
+	spans := t.Spans()
+	root, requests := spans[0], spans[1:]
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Trace %v\n", root.TraceID)
+	fmt.Fprintf(tw, "  %v %v\n", duration(root), root.Name)
+
+	var hosts []string
+	hostTime := map[string]time.Duration{}
+	hostRequests := map[string]int{}
+	for _, span := range requests {
+		status := "-"
+		if code := attribute(span, AttributeHTTPStatusCode); code != nil {
+			status = fmt.Sprint(code)
+		} else if errorType := attribute(span, AttributeErrorType); errorType != nil {
+			status = fmt.Sprint(errorType)
+		}
+		fmt.Fprintf(tw, "    %v\t%v\t%v %v\n", duration(span), status, attribute(span, AttributeHTTPMethod), attribute(span, AttributeURLFull))
+
+		host := fmt.Sprint(attribute(span, AttributeServerAddress))
+		if port := attribute(span, AttributeServerPort); port != nil {
+			host = net.JoinHostPort(host, fmt.Sprint(port))
+		}
+		if _, ok := hostTime[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		hostTime[host] += span.End.Sub(span.Start)
+		hostRequests[host]++
+	}
+
+	if len(hosts) > 0 {
+		sort.SliceStable(hosts, func(i, j int) bool { return hostTime[hosts[i]] > hostTime[hosts[j]] })
+		fmt.Fprintf(tw, "Time by server:\n")
+		for _, host := range hosts {
+			fmt.Fprintf(tw, "  %v\t%v\t%v requests\n", hostTime[host].Round(time.Millisecond), host, hostRequests[host])
+		}
+	}
+
+	return tw.Flush()
+}
+
+func attribute(span *Span, key string) interface{} {
+	for _, attribute := range span.Attributes {
+		if attribute.Key == key {
+			return attribute.Value
+		}
+	}
+	return nil
+}
+
+func duration(span *Span) time.Duration {
+	return span.End.Sub(span.Start).Round(time.Millisecond)
+}
@@ -0,0 +1,181 @@
+// Package tracing records the execution of a command and of its HTTP requests as spans.
+// The spans follow the OpenTelemetry data model, and are exported with the OTLP/JSON encoding,
+// either to a file or to the OTLP/HTTP endpoint of a collector.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// SpanKind tells how a span relates to the other services, as defined by OpenTelemetry
+type SpanKind int
+
+const (
+	// SpanKindInternal is the kind of the span of the command
+	SpanKindInternal SpanKind = 1
+	// SpanKindClient is the kind of the spans of the requests sent to the APIs
+	SpanKindClient SpanKind = 3
+)
+
+// StatusCode is the status of a span, as defined by OpenTelemetry
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key and value describing a span
+type Attribute struct {
+	Key string
+	// Value is a string, int64, float64 or bool
+	Value interface{}
+}
+
+// Span is an operation of the trace: the command, or one of its requests
+type Span struct {
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Name          string
+	Kind          SpanKind
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+
+	trace *Trace
+	ended bool
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+
+	for i := range s.Attributes {
+		if s.Attributes[i].Key == key {
+			s.Attributes[i].Value = value
+			return
+		}
+	}
+	s.Attributes = append(s.Attributes, Attribute{Key: key, Value: value})
+}
+
+// SetStatus sets the status of the span
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+
+	s.Status = code
+	s.StatusMessage = message
+}
+
+// Finish ends the span. Only the first call has an effect.
+func (s *Span) Finish() {
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+
+	s.finish(time.Now())
+}
+
+func (s *Span) finish(end time.Time) {
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.End = end
+}
+
+// Trace is the execution of a command: its root span is the command, and the other spans are its requests
+type Trace struct {
+	Root *Span
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// StartSpan starts a span which is a child of the command
+func (t *Trace) StartSpan(name string, kind SpanKind) *Span {
+	span := &Span{
+		TraceID:      t.Root.TraceID,
+		SpanID:       newID(8),
+		ParentSpanID: t.Root.SpanID,
+		Name:         name,
+		Kind:         kind,
+		Start:        time.Now(),
+		trace:        t,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// Finish ends the command, along with the spans of its requests which did not end yet
+func (t *Trace) Finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	end := time.Now()
+	for _, span := range t.spans {
+		span.finish(end)
+	}
+	if err != nil {
+		t.Root.Status = StatusError
+		t.Root.StatusMessage = err.Error()
+	}
+	t.Root.finish(end)
+}
+
+// Spans returns the spans of the trace, the command first, followed by its requests in the order they started
+func (t *Trace) Spans() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*Span{t.Root}, t.spans...)
+}
+
+// active is the trace of the running command, if it is traced
+var (
+	activeMu sync.Mutex
+	active   *Trace
+)
+
+// Begin starts tracing the command
+func Begin(command string) *Trace {
+	t := &Trace{}
+	t.Root = &Span{
+		TraceID: newID(16),
+		SpanID:  newID(8),
+		Name:    command,
+		Kind:    SpanKindInternal,
+		Start:   time.Now(),
+		trace:   t,
+	}
+
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = t
+	return t
+}
+
+// Active returns the trace of the running command, or nil when the command is not traced
+func Active() *Trace {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	return active
+}
+
+// newID returns a random identifier of the given number of bytes, encoded in hexadecimal
+func newID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -3,11 +3,13 @@ package api
 import (
 	"net/http"
 	"net/url"
+	"strings"
 
 	ocmclustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/apicurio/apicurio-cli/pkg/api/generic"
 	"github.com/apicurio/apicurio-cli/pkg/api/rbac"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
 
 	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
@@ -21,6 +23,7 @@ type API interface {
 	ServiceRegistryInstance(instanceID string) (*registryinstanceclient.APIClient, *registrymgmtclient.Registry, error)
 	RBAC() rbac.RbacAPI
 	GenericAPI() generic.GenericAPI
+	GenericAPIWithBaseURL(baseURL string) generic.GenericAPI
 	GetConfig() Config
 	OCMClustermgmt(apiGateway, accessToken string) (*ocmclustersmgmtv1.Client, func(), error)
 }
@@ -33,4 +36,29 @@ type Config struct {
 	UserAgent   string
 	HTTPClient  *http.Client
 	Logger      logging.Logger
+	// RegistryURL is the URL of the standalone registry the Service Registry instance clients connect to.
+	// When empty, the instances are looked up with the management API.
+	RegistryURL string
+	// AuthProvider authenticates the requests instead of the access token, when set
+	AuthProvider provider.Provider
+}
+
+// StandaloneRegistry describes a standalone registry as a Service Registry instance,
+// so that it can be used where an instance of the management API is expected
+func StandaloneRegistry(registryURL string, instanceID string) *registrymgmtclient.Registry {
+	registryURL = strings.TrimSuffix(registryURL, "/")
+	browserURL := registryURL + "/ui"
+
+	name := registryURL
+	if u, err := url.Parse(registryURL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+
+	return &registrymgmtclient.Registry{
+		Id:          instanceID,
+		Name:        name,
+		Status:      registrymgmtclient.REGISTRYSTATUSVALUE_READY,
+		RegistryUrl: &registryURL,
+		BrowserUrl:  &browserURL,
+	}
 }
@@ -7,12 +7,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	// "github.com/apicurio/apicurio-cli/pkg/shared/connection"
 
 	"github.com/apicurio/apicurio-cli/internal/build"
 	"github.com/apicurio/apicurio-cli/pkg/api/generic"
 	"github.com/apicurio/apicurio-cli/pkg/api/rbac"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
 	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
 	"github.com/apicurio/apicurio-cli/pkg/shared/svcstatus"
 	ocmSdkClient "github.com/openshift-online/ocm-sdk-go"
@@ -21,13 +23,15 @@ import (
 	registryinstanceclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registryinstance/apiv1internal/client"
 	registrymgmt "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1"
 	registrymgmtclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1/client"
-	"golang.org/x/oauth2"
 
 	svcacctmgmt "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/serviceaccountmgmt/apiv1"
 
 	svcacctmgmtclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/serviceaccountmgmt/apiv1/client"
 )
 
+// registryAPIPath is the path of the core Service Registry API
+const registryAPIPath = "/apis/registry/v2"
+
 // defaultAPI is a type which defines a number of API creator functions
 type defaultAPI struct {
 	api.Config
@@ -49,7 +53,6 @@ func (a *defaultAPI) ServiceRegistryMgmt() registrymgmtclient.RegistriesApi {
 	tc := a.CreateOAuthTransport(a.AccessToken)
 	client := registrymgmt.NewAPIClient(&registrymgmt.Config{
 		BaseURL:    a.ApiURL.String(),
-		Debug:      a.Logger.DebugEnabled(),
 		HTTPClient: tc,
 		UserAgent:  build.DefaultUserAgentPrefix + build.Version,
 	})
@@ -62,7 +65,6 @@ func (a *defaultAPI) ServiceAccountMgmt() svcacctmgmtclient.ServiceAccountsApi {
 	tc := a.CreateOAuthTransport(a.AccessToken)
 	client := svcacctmgmt.NewAPIClient(&svcacctmgmt.Config{
 		BaseURL:    a.AuthURL.String(),
-		Debug:      a.Logger.DebugEnabled(),
 		HTTPClient: tc,
 		UserAgent:  a.UserAgent,
 	})
@@ -71,11 +73,18 @@ func (a *defaultAPI) ServiceAccountMgmt() svcacctmgmtclient.ServiceAccountsApi {
 }
 
 // ServiceRegistryInstance returns a new Service Registry API client instance, with the Registry configuration object
+// A standalone registry is used directly, without the management API.
 func (a *defaultAPI) ServiceRegistryInstance(instanceID string) (*registryinstanceclient.APIClient, *registrymgmtclient.Registry, error) {
+	if a.RegistryURL != "" {
+		return a.standaloneRegistryInstance(instanceID)
+	}
+
 	registryAPI := a.ServiceRegistryMgmt()
 
 	instance, resp, err := registryAPI.GetRegistry(context.Background(), instanceID).Execute()
-	defer resp.Body.Close()
+	if resp != nil {
+		defer resp.Body.Close()
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w", err)
 	}
@@ -115,30 +124,45 @@ func (a *defaultAPI) ServiceRegistryInstance(instanceID string) (*registryinstan
 		apiURL.Scheme = "http"
 		apiURL.Path = "/data/registry"
 		baseURL = apiURL.String()
-		fmt.Println(baseURL)
 	} else {
-		baseURL = registryUrl + "/apis/registry/v2"
+		baseURL = registryUrl + registryAPIPath
 	}
 
-	a.Logger.Debugf("Making request to %v", baseURL)
+	return a.registryInstanceClient(baseURL), &instance, nil
+}
+
+// standaloneRegistryInstance returns a Service Registry API client for the standalone registry
+func (a *defaultAPI) standaloneRegistryInstance(instanceID string) (*registryinstanceclient.APIClient, *registrymgmtclient.Registry, error) {
+	instance := api.StandaloneRegistry(a.RegistryURL, instanceID)
 
-	token := a.AccessToken
+	baseURL := instance.GetRegistryUrl()
+	if !strings.HasSuffix(baseURL, registryAPIPath) {
+		baseURL += registryAPIPath
+	}
+
+	return a.registryInstanceClient(baseURL), instance, nil
+}
 
-	client := registryinstance.NewAPIClient(&registryinstance.Config{
-		BaseURL:    "http://localhost:8080/apis/registry/v2",
-		Debug:      a.Logger.DebugEnabled(),
-		HTTPClient: a.CreateOAuthTransport(token),
+func (a *defaultAPI) registryInstanceClient(baseURL string) *registryinstanceclient.APIClient {
+	a.Logger.Debugf("Making request to %v", baseURL)
+
+	return registryinstance.NewAPIClient(&registryinstance.Config{
+		BaseURL:    baseURL,
+		HTTPClient: a.CreateOAuthTransport(a.AccessToken),
 		UserAgent:  build.DefaultUserAgentPrefix + build.Version,
 	})
-
-	return client, &instance, nil
 }
 
 func (a *defaultAPI) GenericAPI() generic.GenericAPI {
+	return a.GenericAPIWithBaseURL(a.ApiURL.String())
+}
+
+// GenericAPIWithBaseURL returns a generic API client which sends requests to the given base URL
+// using the same authenticated transport as the other API clients
+func (a *defaultAPI) GenericAPIWithBaseURL(baseURL string) generic.GenericAPI {
 	tc := a.CreateOAuthTransport(a.AccessToken)
 	client := generic.NewGenericAPIClient(&generic.Config{
-		BaseURL:    a.ApiURL.String(),
-		Debug:      a.Logger.DebugEnabled(),
+		BaseURL:    baseURL,
 		HTTPClient: tc,
 	})
 
@@ -152,7 +176,6 @@ func (a *defaultAPI) RBAC() rbac.RbacAPI {
 			cl := a.CreateOAuthTransport(a.AccessToken)
 			cfg := rbac.Config{
 				HTTPClient: cl,
-				Debug:      a.Logger.DebugEnabled(),
 				BaseURL:    a.ConsoleURL,
 			}
 			return rbac.NewPrincipalAPIClient(&cfg)
@@ -161,19 +184,16 @@ func (a *defaultAPI) RBAC() rbac.RbacAPI {
 	return rbacAPI
 }
 
-// wraps the HTTP client with an OAuth2 Transport layer to provide automatic token refreshing
+// wraps the HTTP client with the transport of the authentication provider of the context,
+// or with an OAuth2 Transport layer which sets the access token
 func (a *defaultAPI) CreateOAuthTransport(accessToken string) *http.Client {
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{
-			AccessToken: accessToken,
-		},
-	)
+	var authProvider provider.Provider = &provider.Bearer{Token: accessToken}
+	if a.AuthProvider != nil {
+		authProvider = a.AuthProvider
+	}
 
 	return &http.Client{
-		Transport: &oauth2.Transport{
-			Base:   a.HTTPClient.Transport,
-			Source: oauth2.ReuseTokenSource(nil, ts),
-		},
+		Transport: authProvider.Transport(a.HTTPClient.Transport),
 	}
 }
 
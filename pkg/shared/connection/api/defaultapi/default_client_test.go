@@ -0,0 +1,104 @@
+package defaultapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
+	"github.com/apicurio/apicurio-cli/pkg/core/logging"
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
+)
+
+func TestStandaloneRegistryInstance(t *testing.T) {
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `["VALIDITY"]`)
+	}))
+	defer srv.Close()
+
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		registryURL  string
+		accessToken  string
+		authProvider provider.Provider
+		wantAuth     string
+	}{
+		{name: "anonymous", registryURL: srv.URL, authProvider: &provider.None{}, wantAuth: ""},
+		{name: "logged in", registryURL: srv.URL + "/", accessToken: "token", wantAuth: "Bearer token"},
+		{name: "core API URL", registryURL: srv.URL + "/apis/registry/v2", authProvider: &provider.None{}, wantAuth: ""},
+		{name: "basic auth provider", registryURL: srv.URL, accessToken: "token", authProvider: &provider.Basic{Username: "user", Password: "pass"}, wantAuth: "Basic dXNlcjpwYXNz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(&api.Config{
+				AccessToken:  tt.accessToken,
+				HTTPClient:   srv.Client(),
+				Logger:       logger,
+				RegistryURL:  tt.registryURL,
+				AuthProvider: tt.authProvider,
+			})
+
+			client, registry, err := a.ServiceRegistryInstance("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registry.GetRegistryUrl() == "" {
+				t.Error("ServiceRegistryInstance() registry has no URL")
+			}
+
+			rules, httpRes, err := client.AdminApi.ListGlobalRules(context.Background()).Execute()
+			if httpRes != nil {
+				defer httpRes.Body.Close()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != 1 {
+				t.Errorf("ListGlobalRules() = %v, want one rule", rules)
+			}
+			if gotPath != "/apis/registry/v2/admin/rules" {
+				t.Errorf("request path = %v, want /apis/registry/v2/admin/rules", gotPath)
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("Authorization header = %q, want %q", gotAuth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestServiceRegistryInstanceTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	apiURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the requests to the management API fail without a response
+	srv.Close()
+
+	logger, err := logging.NewStdLoggerBuilder().Streams(io.Discard, io.Discard).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := New(&api.Config{
+		AccessToken: "token",
+		HTTPClient:  srv.Client(),
+		Logger:      logger,
+		ApiURL:      apiURL,
+	})
+
+	if _, _, err := a.ServiceRegistryInstance("registry"); err == nil {
+		t.Error("ServiceRegistryInstance() should fail when the management API cannot be reached")
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
 	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
 	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
 
 	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
 	"github.com/apicurio/apicurio-cli/pkg/core/logging"
 
 	"github.com/apicurio/apicurio-cli/internal/build"
@@ -23,15 +25,20 @@ import (
 // Don't create instances of this type directly, use the NewConnectionBuilder function instead
 type ConnectionBuilder struct {
 	trustedCAs        *x509.CertPool
+	certificates      []tls.Certificate
+	proxy             func(*http.Request) (*url.URL, error)
 	insecure          bool
 	disableKeepAlives bool
 	accessToken       string
 	refreshToken      string
 	clientID          string
+	clientSecret      string
 	scopes            []string
 	apiURL            string
 	authURL           string
 	consoleURL        string
+	registryURL       string
+	authProvider      provider.Provider
 	config            config.IConfig
 	logger            logging.Logger
 	transportWrapper  TransportWrapper
@@ -68,6 +75,19 @@ func (b *ConnectionBuilder) WithTrustedCAs(value *x509.CertPool) *ConnectionBuil
 	return b
 }
 
+// WithClientCertificates sets the certificates presented to the servers which require mutual TLS
+func (b *ConnectionBuilder) WithClientCertificates(certificates ...tls.Certificate) *ConnectionBuilder {
+	b.certificates = append(b.certificates, certificates...)
+	return b
+}
+
+// WithProxy sets the function which selects the proxy of the requests.
+// The proxy defaults to the one of the environment.
+func (b *ConnectionBuilder) WithProxy(proxy func(*http.Request) (*url.URL, error)) *ConnectionBuilder {
+	b.proxy = proxy
+	return b
+}
+
 func (b *ConnectionBuilder) WithInsecure(insecure bool) *ConnectionBuilder {
 	b.insecure = insecure
 	return b
@@ -98,6 +118,27 @@ func (b *ConnectionBuilder) WithClientID(clientID string) *ConnectionBuilder {
 	return b
 }
 
+// WithClientSecret sets the secret of the service account,
+// which is used to request new access tokens with the client credentials grant
+func (b *ConnectionBuilder) WithClientSecret(clientSecret string) *ConnectionBuilder {
+	b.clientSecret = clientSecret
+	return b
+}
+
+// WithRegistryURL sets the URL of the standalone registry to connect to instead of the management API.
+// The connection is anonymous when the user is not logged in.
+func (b *ConnectionBuilder) WithRegistryURL(registryURL string) *ConnectionBuilder {
+	b.registryURL = registryURL
+	return b
+}
+
+// WithAuthProvider sets the authentication provider of the context,
+// which authenticates the requests instead of the login of the user
+func (b *ConnectionBuilder) WithAuthProvider(authProvider provider.Provider) *ConnectionBuilder {
+	b.authProvider = authProvider
+	return b
+}
+
 func (b *ConnectionBuilder) WithScopes(scopes ...string) *ConnectionBuilder {
 	b.scopes = append(b.scopes, scopes...)
 	return b
@@ -130,11 +171,13 @@ func (b *ConnectionBuilder) Build() (connection *Connection, err error) {
 // the connection, and an error if something fails when trying to create it.
 // nolint:funlen
 func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Connection, err error) {
-	if b.accessToken == "" && b.refreshToken == "" {
+	// standalone registries and contexts with their own authentication provider can be used without logging in
+	anonymous := b.accessToken == "" && b.refreshToken == "" && b.clientSecret == ""
+	if anonymous && b.registryURL == "" && b.authProvider == nil {
 		return nil, &AuthError{notLoggedInError()}
 	}
 
-	if b.clientID == "" {
+	if b.clientID == "" && !anonymous {
 		return nil, AuthErrorf("missing client ID")
 	}
 
@@ -163,7 +206,8 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 	if err != nil {
 		return nil, err
 	}
-	if !tokenIsValid {
+	// service accounts request a new access token instead
+	if !tokenIsValid && b.clientSecret == "" && !anonymous {
 		return nil, sessionExpiredError()
 	}
 
@@ -197,18 +241,9 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 	consoleURL, err := url.Parse(b.consoleURL)
 	if err != nil {
 		err = fmt.Errorf("unable to parse Console URL '%s': %w", b.consoleURL, err)
-	}
-
-	// Create the transport:
-	transport := b.createTransport()
-	if err != nil {
 		return
 	}
 
-	client := &http.Client{
-		Transport: transport,
-	}
-
 	baseAuthURL := fmt.Sprintf("%v://%v", authURL.Scheme, authURL.Host)
 
 	_, kcRealm, ok := SplitKeycloakRealmURL(authURL)
@@ -218,45 +253,76 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 
 	keycloak := gocloak.NewClient(baseAuthURL)
 	restyClient := *keycloak.RestyClient()
-	// #nosec 402
-	restyClient.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: b.insecure})
+	restyClient.SetTransport(b.createAuthTransport())
 	keycloak.SetRestyClient(&restyClient)
 
 	connection = &Connection{
-		insecure:          b.insecure,
-		trustedCAs:        b.trustedCAs,
-		clientID:          b.clientID,
-		consoleURL:        consoleURL,
-		scopes:            scopes,
-		apiURL:            apiURL,
-		authURL:           authURL,
-		defaultHTTPClient: client,
-		keycloakClient:    keycloak,
-		Token:             &tkn,
-		defaultRealm:      kcRealm,
-		logger:            b.logger,
-		Config:            b.config,
+		insecure:       b.insecure,
+		trustedCAs:     b.trustedCAs,
+		clientID:       b.clientID,
+		clientSecret:   b.clientSecret,
+		consoleURL:     consoleURL,
+		registryURL:    b.registryURL,
+		authProvider:   b.authProvider,
+		scopes:         scopes,
+		apiURL:         apiURL,
+		authURL:        authURL,
+		keycloakClient: keycloak,
+		Token:          &tkn,
+		defaultRealm:   kcRealm,
+		logger:         b.logger,
+		Config:         b.config,
+	}
+
+	// Create the transport, which keeps the tokens of the connection valid:
+	connection.defaultHTTPClient = &http.Client{
+		Transport: b.createTransport(connection),
 	}
 
 	return connection, nil
 }
 
-func (b *ConnectionBuilder) createTransport() (transport http.RoundTripper) {
-	// Create the raw transport:
-	// #nosec 402
-	transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: b.insecure,
-			RootCAs:            b.trustedCAs,
-		},
-		Proxy:             http.ProxyFromEnvironment,
-		DisableKeepAlives: b.disableKeepAlives,
-	}
+func (b *ConnectionBuilder) createTransport(tokens httputil.TokenSource) (transport http.RoundTripper) {
+	transport = b.createRawTransport()
 
 	// Wrap the transport with the round trippers provided by the user:
 	if b.transportWrapper != nil {
 		transport = b.transportWrapper(transport)
 	}
 
+	// Refresh the access token of the user before it expires, and when it is rejected:
+	transport = &httputil.RefreshingRoundTripper{
+		Proxied: transport,
+		Source:  tokens,
+	}
+
 	return
 }
+
+// createAuthTransport creates the transport of the requests to the authentication server,
+// which are wrapped like the requests to the APIs so that token requests are logged and recorded too
+func (b *ConnectionBuilder) createAuthTransport() http.RoundTripper {
+	if b.transportWrapper != nil {
+		return b.transportWrapper(b.createRawTransport())
+	}
+	return &httputil.TracingRoundTripper{Proxied: b.createRawTransport()}
+}
+
+// createRawTransport creates the transport of the requests to the APIs and the authentication server
+func (b *ConnectionBuilder) createRawTransport() *http.Transport {
+	proxy := b.proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+
+	// #nosec 402
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: b.insecure,
+			RootCAs:            b.trustedCAs,
+			Certificates:       b.certificates,
+		},
+		Proxy:             proxy,
+		DisableKeepAlives: b.disableKeepAlives,
+	}
+}
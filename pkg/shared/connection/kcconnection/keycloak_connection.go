@@ -5,7 +5,9 @@ import (
 	"crypto/x509"
 	"net/http"
 	"net/url"
+	"sync"
 
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
 	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
 
 	"github.com/apicurio/apicurio-cli/pkg/core/config"
@@ -29,23 +31,121 @@ type Connection struct {
 	insecure          bool
 	defaultHTTPClient *http.Client
 	clientID          string
+	clientSecret      string
 	Token             *token.Token
 	scopes            []string
 	keycloakClient    gocloak.GoCloak
 	apiURL            *url.URL
 	authURL           *url.URL
 	consoleURL        *url.URL
+	registryURL       string
+	authProvider      provider.Provider
 	defaultRealm      string
 	logger            logging.Logger
 	Config            config.IConfig
+
+	// mu guards the tokens, which the transport refreshes while requests are sent concurrently
+	mu sync.Mutex
+	// issued are the previous access tokens of the user, which requests built before a refresh may still carry
+	issued map[string]bool
+	// renewed is the access token obtained by the latest refresh
+	renewed string
 }
 
 // RefreshTokens will fetch a refreshed copy of the access token and refresh token from the authentication server
 // The new tokens will have an increased expiry time and are persisted in the config and connection
+// Service accounts without a refresh token request a new access token with the client credentials grant once it expires.
 func (c *Connection) RefreshTokens(ctx context.Context) (err error) {
-	cfg, err := c.Config.Load()
-	if err != nil {
-		return err
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refreshTokens(ctx, false)
+}
+
+// Issued reports whether the access token was issued to the user of the connection
+func (c *Connection) Issued(accessToken string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return accessToken != "" && (accessToken == c.Token.AccessToken || c.issued[accessToken])
+}
+
+// AccessToken returns the current access token, refreshing it first when it is about to expire.
+// The transport of the connection calls it before each request.
+func (c *Connection) AccessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// a token which was just renewed is not renewed again before each request, even when it is short-lived
+	if c.Token.AccessToken != c.renewed && c.Token.NeedsRefresh() {
+		if err := c.renewTokens(ctx); err != nil {
+			// the current token may still be accepted, and is refreshed again if it is rejected
+			c.logger.Debug("Unable to refresh the tokens before the request:", err)
+		}
+	}
+
+	return c.Token.AccessToken, nil
+}
+
+// RefreshAccessToken refreshes the access token after a server rejected it, unless it was already refreshed.
+// The transport of the connection calls it when a request is rejected with 401 Unauthorized.
+func (c *Connection) RefreshAccessToken(ctx context.Context, rejected string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Token.AccessToken != rejected {
+		return c.Token.AccessToken, nil
+	}
+
+	if err := c.renewTokens(ctx); err != nil {
+		return "", err
+	}
+	return c.Token.AccessToken, nil
+}
+
+// renewTokens replaces the access token, using the tokens which another process saved to the config
+// when they are still valid, so that refresh tokens rotated by the other process are not used again
+func (c *Connection) renewTokens(ctx context.Context) error {
+	// a token which could not be renewed is not renewed again before each request
+	defer func() {
+		c.renewed = c.Token.AccessToken
+	}()
+
+	if cfg, err := c.Config.Load(); err == nil && cfg.AccessToken != "" && cfg.AccessToken != c.Token.AccessToken {
+		stored := token.Token{AccessToken: cfg.AccessToken, RefreshToken: cfg.RefreshToken, Logger: c.logger}
+		if !stored.NeedsRefresh() {
+			c.logger.Debug("Using the tokens refreshed by another process")
+			c.setTokens(stored.AccessToken, stored.RefreshToken)
+			return nil
+		}
+	}
+
+	return c.refreshTokens(ctx, true)
+}
+
+// setTokens replaces the tokens of the connection, keeping track of the access tokens issued to the user
+func (c *Connection) setTokens(accessToken string, refreshToken string) {
+	if c.issued == nil {
+		c.issued = make(map[string]bool)
+	}
+	if c.Token.AccessToken != "" {
+		c.issued[c.Token.AccessToken] = true
+	}
+
+	c.Token.AccessToken = accessToken
+	c.Token.RefreshToken = refreshToken
+	c.renewed = accessToken
+}
+
+// refreshTokens refreshes the tokens. The access token of service accounts is only renewed
+// when it is about to expire, unless force is set.
+func (c *Connection) refreshTokens(ctx context.Context, force bool) (err error) {
+	// the authentication provider of the context does not use the tokens of the user
+	if c.isAnonymous() || c.authProvider != nil {
+		return nil
+	}
+	if c.clientSecret != "" && c.Token.RefreshToken == "" {
+		return c.loginClient(ctx, force)
 	}
 
 	// track if we need to update the config with new token values
@@ -58,14 +158,8 @@ func (c *Connection) RefreshTokens(ctx context.Context) (err error) {
 		return &AuthError{err}
 	}
 
-	if refreshedTk.AccessToken != c.Token.AccessToken {
-		c.Token.AccessToken = refreshedTk.AccessToken
-		cfg.AccessToken = refreshedTk.AccessToken
-		cfgChanged = true
-	}
-	if refreshedTk.RefreshToken != c.Token.RefreshToken {
-		c.Token.RefreshToken = refreshedTk.RefreshToken
-		cfg.RefreshToken = refreshedTk.RefreshToken
+	if refreshedTk.AccessToken != c.Token.AccessToken || refreshedTk.RefreshToken != c.Token.RefreshToken {
+		c.setTokens(refreshedTk.AccessToken, refreshedTk.RefreshToken)
 		cfgChanged = true
 	}
 
@@ -73,7 +167,14 @@ func (c *Connection) RefreshTokens(ctx context.Context) (err error) {
 		return nil
 	}
 
-	if err = c.Config.Save(cfg); err != nil {
+	// other processes may have changed the config since it was loaded,
+	// so only the tokens are written back
+	err = c.Config.Update(func(cfg *config.Config) error {
+		cfg.AccessToken = c.Token.AccessToken
+		cfg.RefreshToken = c.Token.RefreshToken
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 	c.logger.Debug("Tokens refreshed")
@@ -81,39 +182,85 @@ func (c *Connection) RefreshTokens(ctx context.Context) (err error) {
 	return nil
 }
 
+// loginClient requests a new access token for the service account when the current one is missing or expiring,
+// or when force is set
+func (c *Connection) loginClient(ctx context.Context, force bool) error {
+	if !force && !c.Token.NeedsRefresh() {
+		return nil
+	}
+
+	c.logger.Debug("Requesting a new access token with the client credentials grant")
+	jwt, err := c.keycloakClient.LoginClient(ctx, c.clientID, c.clientSecret, c.defaultRealm)
+	if err != nil {
+		return &AuthError{err}
+	}
+
+	c.setTokens(jwt.AccessToken, c.Token.RefreshToken)
+	err = c.Config.Update(func(cfg *config.Config) error {
+		cfg.AccessToken = jwt.AccessToken
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.logger.Debug("Access token renewed")
+
+	return nil
+}
+
 // Logout logs the user out from the authentication server
 // Invalidating and removing the access and refresh tokens
 // The user will have to log in again to access the API
 func (c *Connection) Logout(ctx context.Context) (err error) {
-	err = c.keycloakClient.Logout(ctx, c.clientID, "", c.defaultRealm, c.Token.RefreshToken)
-	if err != nil {
-		return &AuthError{err}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isAnonymous() {
+		return &AuthError{notLoggedInError()}
+	}
+
+	// service accounts logged in with the client credentials grant have no session to end
+	if c.clientSecret == "" || c.Token.RefreshToken != "" {
+		err = c.keycloakClient.Logout(ctx, c.clientID, "", c.defaultRealm, c.Token.RefreshToken)
+		if err != nil {
+			return &AuthError{err}
+		}
 	}
 
 	c.Token.AccessToken = ""
 	c.Token.RefreshToken = ""
+	c.clientSecret = ""
 
-	cfg, err := c.Config.Load()
-	if err != nil {
-		return err
-	}
-
-	cfg.AccessToken = ""
-	cfg.RefreshToken = ""
+	return c.Config.Update(func(cfg *config.Config) error {
+		cfg.AccessToken = ""
+		cfg.RefreshToken = ""
+		cfg.ClientSecret = ""
+		return nil
+	})
+}
 
-	return c.Config.Save(cfg)
+// isAnonymous reports whether the connection is used without logging in
+func (c *Connection) isAnonymous() bool {
+	return c.Token.AccessToken == "" && c.Token.RefreshToken == "" && c.clientSecret == ""
 }
 
 // API Creates a new API type which is a single type for multiple APIs
 func (c *Connection) API() api.API {
+	authProvider := c.authProvider
+	if authProvider == nil && c.isAnonymous() {
+		authProvider = &provider.None{}
+	}
+
 	apiClient := defaultapi.New(&api.Config{
-		HTTPClient:  c.defaultHTTPClient,
-		UserAgent:   build.DefaultUserAgentPrefix + build.Version,
-		AccessToken: c.Token.AccessToken,
-		ApiURL:      c.apiURL,
-		AuthURL:     c.authURL,
-		ConsoleURL:  c.consoleURL,
-		Logger:      c.logger,
+		HTTPClient:   c.defaultHTTPClient,
+		UserAgent:    build.DefaultUserAgentPrefix + build.Version,
+		AccessToken:  c.Token.AccessToken,
+		ApiURL:       c.apiURL,
+		AuthURL:      c.authURL,
+		ConsoleURL:   c.consoleURL,
+		Logger:       c.logger,
+		RegistryURL:  c.registryURL,
+		AuthProvider: authProvider,
 	})
 
 	return apiClient
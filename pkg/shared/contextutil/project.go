@@ -0,0 +1,44 @@
+package contextutil
+
+import (
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const tableOutputFormat = "table"
+
+// ApplyProjectDefaults sets the default artifact group and output format of the project-local context
+// on the "group" and "output" flags of the command, unless they were set by the user.
+// The group only replaces the default artifact group, so that flags where an empty group
+// has another meaning are left untouched. The output format only applies to commands
+// which print a document by default, and the table format to commands which print tables by default.
+func ApplyProjectDefaults(cmd *cobra.Command, project *servicecontext.ProjectConfig) error {
+	if project == nil {
+		return nil
+	}
+
+	flags := cmd.Flags()
+
+	if group := flags.Lookup("group"); project.Group != "" && isDefault(group) && group.DefValue == registrycmdutil.DefaultArtifactGroup {
+		if err := group.Value.Set(project.Group); err != nil {
+			return err
+		}
+	}
+
+	if output := flags.Lookup("output"); project.Output != "" && isDefault(output) && output.DefValue != "" {
+		if project.Output == tableOutputFormat && output.DefValue != tableOutputFormat {
+			return nil
+		}
+		if err := output.Value.Set(project.Output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isDefault(flag *pflag.Flag) bool {
+	return flag != nil && !flag.Changed
+}
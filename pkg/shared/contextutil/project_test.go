@@ -0,0 +1,45 @@
+package contextutil
+
+import (
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/cmd/registry/registrycmdutil"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyProjectDefaults(t *testing.T) {
+	project := &servicecontext.ProjectConfig{Group: "project-group", Output: "table"}
+
+	tests := []struct {
+		name          string
+		groupDefault  string
+		outputDefault string
+		args          []string
+		wantGroup     string
+		wantOutput    string
+	}{
+		{name: "Should apply the project defaults", groupDefault: registrycmdutil.DefaultArtifactGroup, outputDefault: "table", wantGroup: "project-group", wantOutput: "table"},
+		{name: "Should keep the flags set by the user", groupDefault: registrycmdutil.DefaultArtifactGroup, outputDefault: "table", args: []string{"--group", "g", "-o", "json"}, wantGroup: "g", wantOutput: "json"},
+		{name: "Should not apply the group to flags with another default", groupDefault: "", outputDefault: "table", wantGroup: "", wantOutput: "table"},
+		{name: "Should not apply tables to commands without tables by default", groupDefault: registrycmdutil.DefaultArtifactGroup, outputDefault: "json", wantGroup: "project-group", wantOutput: "json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var group, output string
+			cmd := &cobra.Command{}
+			cmd.Flags().StringVarP(&group, "group", "g", tt.groupDefault, "")
+			cmd.Flags().StringVarP(&output, "output", "o", tt.outputDefault, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ApplyProjectDefaults(cmd, project); err != nil {
+				t.Fatalf("ApplyProjectDefaults() error = %v", err)
+			}
+			if group != tt.wantGroup || output != tt.wantOutput {
+				t.Errorf("ApplyProjectDefaults() group = %v, output = %v, want %v and %v", group, output, tt.wantGroup, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/apicurio/apicurio-cli/pkg/core/localize"
 	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
 
+	"github.com/apicurio/apicurio-cli/pkg/shared/connection/api"
 	"github.com/apicurio/apicurio-cli/pkg/shared/factory"
 	srsmgmtv1errors "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1/error"
+	"github.com/spf13/cobra"
 
 	registrymgmtclient "github.com/redhat-developer/app-services-sdk-core/app-services-sdk-go/registrymgmt/apiv1/client"
 )
@@ -17,7 +19,7 @@ func GetContext(svcContext *servicecontext.Context, localizer localize.Localizer
 
 	ctx, ok := svcContext.Contexts[ctxName]
 	if !ok {
-		return nil, localizer.MustLocalizeError("context.common.error.context.notFound", localize.NewEntry("Name", svcContext.CurrentContext))
+		return nil, localizer.MustLocalizeError("context.common.error.context.notFound", localize.NewEntry("Name", ctxName))
 	}
 
 	return &ctx, nil
@@ -27,6 +29,14 @@ func GetContext(svcContext *servicecontext.Context, localizer localize.Localizer
 // GetCurrentContext returns the name of the currently selected context
 func GetCurrentContext(svcContext *servicecontext.Context, localizer localize.Localizer) (*servicecontext.ServiceConfig, error) {
 
+	if svcContext.ProjectError != nil {
+		return nil, svcContext.ProjectError
+	}
+
+	if project := svcContext.Project; project != nil && (project.Context != "" || project.ServiceRegistryID != "") {
+		return getProjectContext(svcContext, localizer)
+	}
+
 	if svcContext.CurrentContext == "" {
 		return nil, localizer.MustLocalizeError("context.common.error.notSet")
 	}
@@ -39,6 +49,45 @@ func GetCurrentContext(svcContext *servicecontext.Context, localizer localize.Lo
 	return &currCtx, nil
 }
 
+// getProjectContext returns the services of the project-local context.
+// The project is based on its named context, or on the current context when it has none,
+// and the services pinned by the project take precedence.
+func getProjectContext(svcContext *servicecontext.Context, localizer localize.Localizer) (*servicecontext.ServiceConfig, error) {
+	project := svcContext.Project
+
+	var svcConfig servicecontext.ServiceConfig
+	if project.Context != "" {
+		ctx, ok := svcContext.Contexts[project.Context]
+		if !ok {
+			return nil, localizer.MustLocalizeError("context.common.error.context.notFound", localize.NewEntry("Name", project.Context))
+		}
+		svcConfig = ctx
+	} else if ctx, ok := svcContext.Contexts[svcContext.CurrentContext]; ok {
+		svcConfig = ctx
+	}
+
+	if project.ServiceRegistryID != "" {
+		svcConfig.ServiceRegistryID = project.ServiceRegistryID
+	}
+
+	return &svcConfig, nil
+}
+
+// CheckInstanceIDFlag fails when the "instance-id" flag of the command is set while the active context
+// connects to a standalone registry, as the standalone registry is always used in that context
+func CheckInstanceIDFlag(cmd *cobra.Command, svcContext *servicecontext.Context, localizer localize.Localizer) error {
+	flag := cmd.Flags().Lookup("instance-id")
+	if flag == nil || !flag.Changed {
+		return nil
+	}
+
+	name := svcContext.ActiveName()
+	if currCtx, ok := svcContext.Contexts[name]; ok && currCtx.IsStandalone() {
+		return localizer.MustLocalizeError("context.common.error.standaloneInstanceID", localize.NewEntry("Name", name))
+	}
+	return nil
+}
+
 // GetCurrentRegistryInstance returns the Service Registry instance set in the currently selected context
 func GetCurrentRegistryInstance(f *factory.Factory) (*registrymgmtclient.Registry, error) {
 
@@ -56,7 +105,13 @@ func GetCurrentRegistryInstance(f *factory.Factory) (*registrymgmtclient.Registr
 
 }
 
+// GetRegistryForServiceConfig returns the Service Registry instance set in the context.
+// The standalone registry of the context is returned without using the management API.
 func GetRegistryForServiceConfig(currCtx *servicecontext.ServiceConfig, f *factory.Factory) (*registrymgmtclient.Registry, error) {
+	if currCtx.IsStandalone() {
+		return api.StandaloneRegistry(currCtx.RegistryURL, currCtx.ServiceRegistryID), nil
+	}
+
 	conn, err := f.Connection()
 	if err != nil {
 		return nil, err
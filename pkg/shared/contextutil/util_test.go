@@ -0,0 +1,48 @@
+package contextutil
+
+import (
+	"testing"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/spf13/cobra"
+)
+
+func TestCheckInstanceIDFlag(t *testing.T) {
+	localizer, err := goi18n.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcContext := &servicecontext.Context{
+		Contexts: map[string]servicecontext.ServiceConfig{
+			"managed":    {ServiceRegistryID: "registry"},
+			"standalone": {RegistryURL: "http://localhost:8080"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		context string
+		args    []string
+		wantErr bool
+	}{
+		{name: "Should allow the flag in a managed context", context: "managed", args: []string{"--instance-id", "other"}},
+		{name: "Should reject the flag in a standalone context", context: "standalone", args: []string{"--instance-id", "other"}, wantErr: true},
+		{name: "Should allow a standalone context without the flag", context: "standalone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var registryID string
+			cmd := &cobra.Command{}
+			cmd.Flags().StringVar(&registryID, "instance-id", "", "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			svcContext.CurrentContext = tt.context
+			if err := CheckInstanceIDFlag(cmd, svcContext, localizer); (err != nil) != tt.wantErr {
+				t.Errorf("CheckInstanceIDFlag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
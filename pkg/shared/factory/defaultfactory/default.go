@@ -2,8 +2,14 @@ package defaultfactory
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/provider"
+	"github.com/apicurio/apicurio-cli/pkg/core/auth/token"
+	"github.com/apicurio/apicurio-cli/pkg/core/cache"
+	"github.com/apicurio/apicurio-cli/pkg/core/cmdutil/flagutil"
 	"github.com/apicurio/apicurio-cli/pkg/core/config"
 	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
 	"github.com/apicurio/apicurio-cli/pkg/core/ioutil/iostreams"
@@ -20,7 +26,11 @@ import (
 
 // New creates a new command factory
 // The command factory is available to all command packages
-// giving centralized access to the config and API connection
+// giving centralized access to the config and API connection.
+//
+// Go tests record the HTTP interactions of the connection to a cassette, or replay them from one,
+// by setting the APICR_RECORD or APICR_REPLAY environment variable (httputil.RecordEnvName and
+// httputil.ReplayEnvName) with t.Setenv before calling the Connection function of the factory.
 
 // nolint:funlen
 func New(localizer localize.Localizer) *factory.Factory {
@@ -28,8 +38,9 @@ func New(localizer localize.Localizer) *factory.Factory {
 
 	var logger logging.Logger
 	var conn connection.Connection
-	cfgFile := config.NewFile()
 	ctxFile := servicecontext.NewFile()
+	// the connection endpoints and tokens follow the selected login profile and the environment of the active context
+	cfgFile := servicecontext.NewEnvironmentConfig(config.NewProfileConfig(config.NewFile(), flagutil.SelectedProfile), ctxFile)
 
 	loggerBuilder := logging.NewStdLoggerBuilder()
 	loggerBuilder = loggerBuilder.Streams(io.Out, io.ErrOut)
@@ -57,6 +68,9 @@ func New(localizer localize.Localizer) *factory.Factory {
 		if cfg.ClientID != "" {
 			builder.WithClientID(cfg.ClientID)
 		}
+		if cfg.ClientSecret != "" {
+			builder.WithClientSecret(cfg.ClientSecret)
+		}
 		if cfg.Scopes != nil {
 			builder.WithScopes(cfg.Scopes...)
 		}
@@ -70,15 +84,80 @@ func New(localizer localize.Localizer) *factory.Factory {
 
 		builder.WithConsoleURL(build.ConsoleURL)
 
-		builder.WithInsecure(cfg.Insecure)
+		var svcConfig *servicecontext.ServiceConfig
+		if svcContext, err := ctxFile.Load(); err == nil {
+			if active, ok := svcContext.Contexts[svcContext.ActiveName()]; ok {
+				svcConfig = &active
+			}
+		}
+
+		var authProvider provider.Provider
+		if svcConfig != nil {
+			builder.WithRegistryURL(svcConfig.RegistryURL)
+			if svcConfig.Auth != nil {
+				authProvider, err = provider.New(svcConfig.Auth)
+				if err != nil {
+					return nil, err
+				}
+				builder.WithAuthProvider(authProvider)
+			}
+		}
+
+		transportSettings := svcConfig.TransportSettings(cfg)
+		builder.WithInsecure(transportSettings.Insecure)
+		trustedCAs, err := transportSettings.TrustedCAs()
+		if err != nil {
+			return nil, err
+		}
+		builder.WithTrustedCAs(trustedCAs)
+		certificates, err := transportSettings.ClientCertificates()
+		if err != nil {
+			return nil, err
+		}
+		builder.WithClientCertificates(certificates...)
+		proxy, err := transportSettings.Proxy()
+		if err != nil {
+			return nil, err
+		}
+		builder.WithProxy(proxy)
 
 		builder.WithConfig(cfgFile)
 
+		// the flags are parsed by now, unlike when the logger was built
+		logger.SetDebug(flagutil.DebugEnabled())
+
+		retrying, err := newRetryingRoundTripper(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		cassette, err := newCassetteWrapper(builder)
+		if err != nil {
+			return nil, err
+		}
+
+		httpLogging, err := newLoggingRoundTripper(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		caching, err := newCachingRoundTripper(cfg, logger, cachePrincipal(cfg, authProvider))
+		if err != nil {
+			return nil, err
+		}
+
 		transportWrapper := func(a http.RoundTripper) http.RoundTripper {
-			return &httputil.LoggingRoundTripper{
-				Proxied: a,
-				Logger:  logger,
+			lrt := *httpLogging
+			lrt.Proxied = &httputil.TracingRoundTripper{Proxied: cassette(a)}
+			rt := *retrying
+			// each attempt of a retried request is logged and traced
+			rt.Proxied = &lrt
+			if caching == nil {
+				return &rt
 			}
+			crt := *caching
+			crt.Proxied = &rt
+			return &crt
 		}
 
 		builder.WithTransportWrapper(transportWrapper)
@@ -106,3 +185,143 @@ func New(localizer localize.Localizer) *factory.Factory {
 		ServiceContext: ctxFile,
 	}
 }
+
+// newRetryingRoundTripper configures the retries of the requests with the config file,
+// overridden by the retry flags
+func newRetryingRoundTripper(cfg *config.Config, logger logging.Logger) (*httputil.RetryingRoundTripper, error) {
+	rt := &httputil.RetryingRoundTripper{
+		Logger:    logger,
+		Retries:   httputil.DefaultRetries,
+		MaxWait:   httputil.DefaultRetryMaxWait,
+		RetryPOST: cfg.RetryPOST,
+	}
+
+	if cfg.Retries != nil {
+		rt.Retries = *cfg.Retries
+	}
+	if retries, ok := flagutil.Retries(); ok {
+		rt.Retries = retries
+	}
+	if rt.Retries < 0 {
+		return nil, fmt.Errorf("invalid number of retries %v: the number must not be negative", rt.Retries)
+	}
+
+	if cfg.RetryMaxWait != "" {
+		maxWait, err := time.ParseDuration(cfg.RetryMaxWait)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry_max_wait \"%v\" in the config file: %w", cfg.RetryMaxWait, err)
+		}
+		rt.MaxWait = maxWait
+	}
+	if maxWait, ok := flagutil.RetryMaxWait(); ok {
+		rt.MaxWait = maxWait
+	}
+
+	return rt, nil
+}
+
+// newLoggingRoundTripper configures the logging of the requests in debug mode with the config file,
+// overridden by the HTTP log flags
+func newLoggingRoundTripper(cfg *config.Config, logger logging.Logger) (*httputil.LoggingRoundTripper, error) {
+	rt := &httputil.LoggingRoundTripper{
+		Logger: logger,
+		Level:  cfg.HTTPLogLevel,
+		Curl:   cfg.HTTPLogCurl,
+	}
+
+	if level, ok := flagutil.HTTPLogLevel(); ok {
+		rt.Level = level
+	}
+	if curl, ok := flagutil.HTTPLogCurl(); ok {
+		rt.Curl = curl
+	}
+
+	if rt.Level == "" {
+		rt.Level = httputil.LogLevelErrors
+	}
+	for _, level := range httputil.LogLevels {
+		if rt.Level == level {
+			return rt, nil
+		}
+	}
+	return nil, flagutil.InvalidValueError("http-log-level", rt.Level, httputil.LogLevels...)
+}
+
+// newCachingRoundTripper configures the cache of the responses with the config file.
+// The cache is not used when it is disabled with the no-cache flag, or when HTTP interactions are recorded or replayed.
+func newCachingRoundTripper(cfg *config.Config, logger logging.Logger, principal string) (*httputil.CachingRoundTripper, error) {
+	if flagutil.CacheDisabled() || flagutil.RecordCassette() != "" || flagutil.ReplayCassette() != "" {
+		return nil, nil
+	}
+
+	c, err := cache.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := &httputil.CachingRoundTripper{
+		Cache:     c,
+		Logger:    logger,
+		Principal: principal,
+		TTL:       cache.DefaultTTL,
+	}
+
+	if cfg.CacheTTL != "" {
+		ttl, err := time.ParseDuration(cfg.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache_ttl \"%v\" in the config file: %w", cfg.CacheTTL, err)
+		}
+		rt.TTL = ttl
+	}
+
+	return rt, nil
+}
+
+// cachePrincipal returns who the requests are sent for: the login profile,
+// and the authentication provider of the context or the user or service account logged in
+func cachePrincipal(cfg *config.Config, authProvider provider.Provider) string {
+	principal := "profile:" + config.ActiveProfile(cfg, flagutil.SelectedProfile()) + "\n"
+	if authProvider != nil {
+		return principal + provider.Principal(authProvider)
+	}
+
+	for _, tokenStr := range []string{cfg.AccessToken, cfg.RefreshToken} {
+		if subject, ok := token.GetSubject(tokenStr); ok {
+			return principal + "user:" + subject
+		}
+	}
+	return principal + "client:" + cfg.ClientID
+}
+
+// newCassetteWrapper returns the transport wrapper which records the HTTP interactions to a cassette,
+// or replays them from one, as selected by the record and replay flags.
+// The requests to the APIs and to the authentication server share the cassette.
+// Replayed requests are sent without authentication, so that no login is needed.
+func newCassetteWrapper(builder *kcconnection.ConnectionBuilder) (kcconnection.TransportWrapper, error) {
+	recordPath, replayPath := flagutil.RecordCassette(), flagutil.ReplayCassette()
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, fmt.Errorf("HTTP interactions cannot be recorded and replayed at once")
+	case recordPath != "":
+		cassette := &httputil.Cassette{}
+		return func(a http.RoundTripper) http.RoundTripper {
+			return &httputil.RecordingRoundTripper{
+				Proxied:  a,
+				Path:     recordPath,
+				Cassette: cassette,
+			}
+		}, nil
+	case replayPath != "":
+		cassette, err := httputil.LoadCassette(replayPath)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithAuthProvider(&provider.None{})
+		replaying := &httputil.ReplayingRoundTripper{Cassette: cassette}
+		return func(http.RoundTripper) http.RoundTripper {
+			return replaying
+		}, nil
+	default:
+		return func(a http.RoundTripper) http.RoundTripper { return a }, nil
+	}
+}
@@ -0,0 +1,157 @@
+package defaultfactory
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apicurio/apicurio-cli/pkg/core/config"
+	"github.com/apicurio/apicurio-cli/pkg/core/httputil"
+	"github.com/apicurio/apicurio-cli/pkg/core/localize/goi18n"
+	"github.com/apicurio/apicurio-cli/pkg/core/servicecontext"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestCassette(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/registry/v2/search/artifacts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"artifacts":[{"id":"orders","groupId":"default","type":"AVRO"}],"count":1}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, "cassette.yaml")
+	files := map[string]string{
+		config.EnvName:                `{}`,
+		servicecontext.ContextEnvName: `{"contexts":{"standalone":{"registry_url":"` + srv.URL + `"}},"current_context":"standalone"}`,
+	}
+	for envName, content := range files {
+		path := filepath.Join(dir, envName+".json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(envName, path)
+	}
+
+	localizer, _ := goi18n.New(nil)
+	searchArtifacts := func(t *testing.T) []string {
+		conn, err := New(localizer).Connection()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, _, err := conn.API().ServiceRegistryInstance("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, _, err := client.SearchApi.SearchArtifacts(context.Background()).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, artifact := range result.GetArtifacts() {
+			ids = append(ids, artifact.GetId())
+		}
+		return ids
+	}
+
+	t.Setenv(httputil.RecordEnvName, cassette)
+	recorded := searchArtifacts(t)
+	srv.Close()
+
+	t.Setenv(httputil.RecordEnvName, "")
+	t.Setenv(httputil.ReplayEnvName, cassette)
+	replayed := searchArtifacts(t)
+
+	if len(recorded) != 1 || len(replayed) != 1 || replayed[0] != recorded[0] {
+		t.Errorf("replayed artifacts = %v, want %v", replayed, recorded)
+	}
+}
+
+func TestCassetteRecordsTokenRefresh(t *testing.T) {
+	newToken := func(t *testing.T, expiresIn time.Duration) string {
+		t.Helper()
+		claims := jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(expiresIn).Unix()}
+		tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tkn
+	}
+	refreshed := newToken(t, time.Hour)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  refreshed,
+				"refresh_token": refreshed,
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+			})
+		case r.URL.Path == "/apis/registry/v2/search/artifacts":
+			_, _ = io.WriteString(w, `{"artifacts":[],"count":0}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, "cassette.yaml")
+	expired := newToken(t, -time.Hour)
+	files := map[string]string{
+		config.EnvName: `{"access_token":"` + expired + `","refresh_token":"` + newToken(t, time.Hour) +
+			`","auth_url":"` + srv.URL + `/auth/realms/test","client_id":"cli"}`,
+		servicecontext.ContextEnvName: `{"contexts":{"standalone":{"registry_url":"` + srv.URL + `"}},"current_context":"standalone"}`,
+	}
+	for envName, content := range files {
+		path := filepath.Join(dir, envName+".json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(envName, path)
+	}
+	t.Setenv(httputil.RecordEnvName, cassette)
+
+	localizer, _ := goi18n.New(nil)
+	conn, err := New(localizer).Connection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, err := conn.API().ServiceRegistryInstance("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.SearchApi.SearchArtifacts(context.Background()).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := httputil.LoadCassette(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refreshes, searches int
+	for _, interaction := range recorded.Interactions {
+		switch {
+		case strings.HasSuffix(interaction.Request.URL, "/protocol/openid-connect/token"):
+			refreshes++
+		case strings.Contains(interaction.Request.URL, "/search/artifacts"):
+			searches++
+		}
+	}
+	if refreshes != 1 || searches != 1 {
+		t.Errorf("cassette has %v token refreshes and %v searches, want 1 of each: %+v", refreshes, searches, recorded.Interactions)
+	}
+}
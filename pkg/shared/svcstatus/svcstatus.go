@@ -7,6 +7,7 @@ const (
 	StatusAccepted     ServiceStatus = "accepted"
 	StatusPreparing    ServiceStatus = "preparing"
 	StatusProvisioning ServiceStatus = "provisioning"
+	StatusReady        ServiceStatus = "ready"
 	StatusFailed       ServiceStatus = "failed"
 	StatusDeprovision  ServiceStatus = "deprovision"
 	StatusDeleting     ServiceStatus = "deleting"